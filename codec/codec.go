@@ -0,0 +1,64 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+// Package codec contains definitions used by code generated with gencodec -typed-errors,
+// and the JSON functions used by code generated with -mode=direct.
+package codec
+
+import "fmt"
+
+// Reasons for a FieldError.
+const (
+	ReasonMissing     = "missing required field"
+	ReasonWrongLength = "wrong length"
+	ReasonConstraint  = "constraint violated"
+	ReasonUnknown     = "unknown field"
+	ReasonAlias       = "field and alias both set"
+	ReasonOutOfRange  = "value out of range"
+)
+
+// FieldError is returned by generated unmarshaling methods when
+// the input contains an invalid field.
+type FieldError struct {
+	Type   string // name of the Go type being decoded
+	Field  string // encoded name of the field
+	Format string // format being decoded, e.g. "json", empty for Validate
+	Reason string // describes the problem, e.g. ReasonMissing
+	Length int    // required number of items for ReasonWrongLength
+
+	// Constraint is the violated option of the gencodec tag for
+	// ReasonConstraint, e.g. "min=1".
+	Constraint string
+
+	// Alias is the alias of the field for ReasonAlias.
+	Alias string
+
+	// Position of the invalid value in the input. These are only set by
+	// formats which track positions, and are zero otherwise.
+	Line, Column int
+}
+
+func (e *FieldError) Error() string {
+	var msg string
+	switch e.Reason {
+	case ReasonMissing:
+		msg = fmt.Sprintf("missing required field '%s' for %s", e.Field, e.Type)
+	case ReasonWrongLength:
+		msg = fmt.Sprintf("field '%s' has wrong length, need %d items", e.Field, e.Length)
+	case ReasonConstraint:
+		msg = fmt.Sprintf("field '%s' violates constraint %s", e.Field, e.Constraint)
+	case ReasonUnknown:
+		msg = fmt.Sprintf("unknown field '%s' for %s", e.Field, e.Type)
+	case ReasonAlias:
+		msg = fmt.Sprintf("field '%s' and its alias '%s' are both set for %s", e.Field, e.Alias, e.Type)
+	case ReasonOutOfRange:
+		msg = fmt.Sprintf("value of field '%s' is out of range for %s", e.Field, e.Type)
+	default:
+		msg = fmt.Sprintf("invalid field '%s' for %s: %s", e.Field, e.Type, e.Reason)
+	}
+	if e.Line > 0 {
+		msg = fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, msg)
+	}
+	return msg
+}
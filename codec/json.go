@@ -0,0 +1,699 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// This file contains the JSON encoding and decoding functions used by code generated
+// with -mode=direct. They follow the behavior of package encoding/json.
+
+// maxJSONDepth is the nesting limit of JSON values, like in package encoding/json.
+const maxJSONDepth = 10000
+
+var errJSONEnd = errors.New("unexpected end of JSON input")
+
+// JSONObject reads the members of a JSON object. The input is scanned once, and
+// all values are checked for valid syntax.
+type JSONObject struct {
+	data       []byte
+	pos        int
+	typeName   string
+	key, value []byte
+	err        error
+	inObject   bool
+	done       bool
+}
+
+// NewJSONObject creates a reader for the object in input. If input is JSON null,
+// the object has no members. For any other non-object value, Err returns an error
+// mentioning typeName.
+func NewJSONObject(input []byte, typeName string) JSONObject {
+	return JSONObject{data: input, typeName: typeName}
+}
+
+// Next advances to the next member of the object. It returns false at the end of
+// the object or when an error occurs.
+func (o *JSONObject) Next() bool {
+	if o.done {
+		return false
+	}
+	o.skipSpace()
+	if !o.inObject {
+		return o.start()
+	}
+	if o.pos == len(o.data) {
+		return o.fail(errJSONEnd)
+	}
+	switch c := o.data[o.pos]; c {
+	case ',':
+		o.pos++
+		return o.readMember()
+	case '}':
+		o.pos++
+		return o.finish()
+	default:
+		return o.fail(syntaxError(c, "after object key:value pair"))
+	}
+}
+
+// Key returns the unescaped key of the current member.
+func (o *JSONObject) Key() []byte {
+	return o.key
+}
+
+// Value returns the JSON value of the current member.
+func (o *JSONObject) Value() []byte {
+	return o.value
+}
+
+// Err returns the error which ended the object, or nil if the input was valid.
+func (o *JSONObject) Err() error {
+	return o.err
+}
+
+// start reads the beginning of the input.
+func (o *JSONObject) start() bool {
+	if o.pos == len(o.data) {
+		return o.fail(errJSONEnd)
+	}
+	switch c := o.data[o.pos]; c {
+	case '{':
+		o.pos++
+		o.inObject = true
+		o.skipSpace()
+		if o.pos < len(o.data) && o.data[o.pos] == '}' {
+			o.pos++
+			return o.finish()
+		}
+		return o.readMember()
+	case 'n':
+		if err := o.scanLiteral("null"); err != nil {
+			return o.fail(err)
+		}
+		return o.finish()
+	default:
+		start := o.pos
+		if err := o.scanValue(0); err != nil {
+			return o.fail(err)
+		}
+		kind := jsonKind(o.data[start:o.pos])
+		return o.fail(fmt.Errorf("json: cannot unmarshal %s into Go value of type %s", kind, o.typeName))
+	}
+}
+
+// readMember reads a key and its value.
+func (o *JSONObject) readMember() bool {
+	o.skipSpace()
+	if o.pos == len(o.data) {
+		return o.fail(errJSONEnd)
+	}
+	if c := o.data[o.pos]; c != '"' {
+		return o.fail(syntaxError(c, "looking for beginning of object key string"))
+	}
+	start := o.pos
+	escaped, err := o.scanString()
+	if err != nil {
+		return o.fail(err)
+	}
+	o.key = o.data[start+1 : o.pos-1]
+	if escaped {
+		o.key = []byte(unquoteJSON(o.key))
+	}
+	o.skipSpace()
+	if o.pos == len(o.data) {
+		return o.fail(errJSONEnd)
+	}
+	if c := o.data[o.pos]; c != ':' {
+		return o.fail(syntaxError(c, "after object key"))
+	}
+	o.pos++
+	o.skipSpace()
+	start = o.pos
+	if err := o.scanValue(1); err != nil {
+		return o.fail(err)
+	}
+	o.value = o.data[start:o.pos]
+	return true
+}
+
+// finish checks that nothing but whitespace follows the value.
+func (o *JSONObject) finish() bool {
+	o.done = true
+	o.skipSpace()
+	if o.pos < len(o.data) {
+		o.err = syntaxError(o.data[o.pos], "after top-level value")
+	}
+	return false
+}
+
+func (o *JSONObject) fail(err error) bool {
+	o.done = true
+	o.err = err
+	return false
+}
+
+func (o *JSONObject) skipSpace() {
+	for o.pos < len(o.data) {
+		switch o.data[o.pos] {
+		case ' ', '\t', '\n', '\r':
+			o.pos++
+		default:
+			return
+		}
+	}
+}
+
+// scanValue moves past the value at the current position.
+func (o *JSONObject) scanValue(depth int) error {
+	if o.pos == len(o.data) {
+		return errJSONEnd
+	}
+	switch c := o.data[o.pos]; {
+	case c == '{' || c == '[':
+		if depth >= maxJSONDepth {
+			return errors.New("exceeded max depth")
+		}
+		return o.scanComposite(depth + 1)
+	case c == '"':
+		_, err := o.scanString()
+		return err
+	case c == 't':
+		return o.scanLiteral("true")
+	case c == 'f':
+		return o.scanLiteral("false")
+	case c == 'n':
+		return o.scanLiteral("null")
+	case c == '-' || (c >= '0' && c <= '9'):
+		return o.scanNumber()
+	default:
+		return syntaxError(c, "looking for beginning of value")
+	}
+}
+
+// scanComposite moves past an object or array.
+func (o *JSONObject) scanComposite(depth int) error {
+	end := byte(']')
+	if o.data[o.pos] == '{' {
+		end = '}'
+	}
+	o.pos++
+	o.skipSpace()
+	if o.pos < len(o.data) && o.data[o.pos] == end {
+		o.pos++
+		return nil
+	}
+	for {
+		o.skipSpace()
+		if end == '}' {
+			if o.pos == len(o.data) {
+				return errJSONEnd
+			}
+			if c := o.data[o.pos]; c != '"' {
+				return syntaxError(c, "looking for beginning of object key string")
+			}
+			if _, err := o.scanString(); err != nil {
+				return err
+			}
+			o.skipSpace()
+			if o.pos == len(o.data) {
+				return errJSONEnd
+			}
+			if c := o.data[o.pos]; c != ':' {
+				return syntaxError(c, "after object key")
+			}
+			o.pos++
+			o.skipSpace()
+		}
+		if err := o.scanValue(depth); err != nil {
+			return err
+		}
+		o.skipSpace()
+		if o.pos == len(o.data) {
+			return errJSONEnd
+		}
+		switch c := o.data[o.pos]; {
+		case c == ',':
+			o.pos++
+		case c == end:
+			o.pos++
+			return nil
+		case end == '}':
+			return syntaxError(c, "after object key:value pair")
+		default:
+			return syntaxError(c, "after array element")
+		}
+	}
+}
+
+// scanString moves past a string. It reports whether the string contains escape
+// sequences or non-ASCII characters.
+func (o *JSONObject) scanString() (escaped bool, err error) {
+	o.pos++
+	for o.pos < len(o.data) {
+		c := o.data[o.pos]
+		switch {
+		case c == '"':
+			o.pos++
+			return escaped, nil
+		case c == '\\':
+			escaped = true
+			o.pos++
+			if o.pos == len(o.data) {
+				return false, errJSONEnd
+			}
+			switch e := o.data[o.pos]; e {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				o.pos++
+			case 'u':
+				o.pos++
+				for i := 0; i < 4; i++ {
+					if o.pos == len(o.data) {
+						return false, errJSONEnd
+					}
+					if !isHex(o.data[o.pos]) {
+						return false, syntaxError(o.data[o.pos], "in \\u hexadecimal character escape")
+					}
+					o.pos++
+				}
+			default:
+				return false, syntaxError(e, "in string escape code")
+			}
+		case c < 0x20:
+			return false, syntaxError(c, "in string literal")
+		default:
+			if c >= utf8.RuneSelf {
+				escaped = true
+			}
+			o.pos++
+		}
+	}
+	return false, errJSONEnd
+}
+
+// scanNumber moves past a number.
+func (o *JSONObject) scanNumber() error {
+	digits := func() int {
+		n := 0
+		for o.pos < len(o.data) && o.data[o.pos] >= '0' && o.data[o.pos] <= '9' {
+			o.pos++
+			n++
+		}
+		return n
+	}
+	next := func(context string) error {
+		if o.pos == len(o.data) {
+			return errJSONEnd
+		}
+		return syntaxError(o.data[o.pos], context)
+	}
+
+	if o.data[o.pos] == '-' {
+		o.pos++
+	}
+	switch {
+	case o.pos < len(o.data) && o.data[o.pos] == '0':
+		o.pos++
+	case digits() == 0:
+		return next("in numeric literal")
+	}
+	if o.pos < len(o.data) && o.data[o.pos] == '.' {
+		o.pos++
+		if digits() == 0 {
+			return next("after decimal point in numeric literal")
+		}
+	}
+	if o.pos < len(o.data) && (o.data[o.pos] == 'e' || o.data[o.pos] == 'E') {
+		o.pos++
+		if o.pos < len(o.data) && (o.data[o.pos] == '+' || o.data[o.pos] == '-') {
+			o.pos++
+		}
+		if digits() == 0 {
+			return next("in exponent of numeric literal")
+		}
+	}
+	return nil
+}
+
+// scanLiteral moves past true, false or null.
+func (o *JSONObject) scanLiteral(lit string) error {
+	for i := 0; i < len(lit); i++ {
+		if o.pos == len(o.data) {
+			return errJSONEnd
+		}
+		if c := o.data[o.pos]; c != lit[i] {
+			return syntaxError(c, "in literal "+lit+" (expecting "+strconv.QuoteRune(rune(lit[i]))+")")
+		}
+		o.pos++
+	}
+	return nil
+}
+
+func syntaxError(c byte, context string) error {
+	return fmt.Errorf("invalid character %s %s", quoteChar(c), context)
+}
+
+// quoteChar formats c for error messages like package encoding/json.
+func quoteChar(c byte) string {
+	if c == '\'' {
+		return `'\''`
+	}
+	if c == '"' {
+		return `'"'`
+	}
+	s := strconv.Quote(string(rune(c)))
+	return "'" + s[1:len(s)-1] + "'"
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// jsonKind returns the kind of a JSON value for error messages.
+func jsonKind(value []byte) string {
+	switch value[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+// unquoteJSON decodes the content of a valid JSON string. Invalid UTF-8 and
+// invalid surrogate pairs are replaced by U+FFFD.
+func unquoteJSON(s []byte) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\':
+			switch e := s[i+1]; e {
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'u':
+				r := getu4(s[i+2:])
+				i += 6
+				if utf16.IsSurrogate(r) {
+					if i+6 <= len(s) && s[i] == '\\' && s[i+1] == 'u' {
+						if dec := utf16.DecodeRune(r, getu4(s[i+2:])); dec != unicode.ReplacementChar {
+							b = utf8.AppendRune(b, dec)
+							i += 6
+							continue
+						}
+					}
+					r = unicode.ReplacementChar
+				}
+				b = utf8.AppendRune(b, r)
+				continue
+			default:
+				b = append(b, e)
+			}
+			i += 2
+		case c < utf8.RuneSelf:
+			b = append(b, c)
+			i++
+		default:
+			r, size := utf8.DecodeRune(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				b = utf8.AppendRune(b, unicode.ReplacementChar)
+			} else {
+				b = append(b, s[i:i+size]...)
+			}
+			i += size
+		}
+	}
+	return string(b)
+}
+
+// getu4 decodes the four hex digits at the beginning of s.
+func getu4(s []byte) rune {
+	var r rune
+	for _, c := range s[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c = c - 'a' + 10
+		default:
+			c = c - 'A' + 10
+		}
+		r = r*16 + rune(c)
+	}
+	return r
+}
+
+// DecodeJSONString decodes a JSON string. The value must be valid JSON, as returned
+// by JSONObject.Value. JSON null sets *dst to nil.
+func DecodeJSONString[T ~string](value []byte, dst **T) error {
+	switch value[0] {
+	case 'n':
+		*dst = nil
+		return nil
+	case '"':
+		s := value[1 : len(value)-1]
+		var v T
+		if needsUnquote(s) {
+			v = T(unquoteJSON(s))
+		} else {
+			v = T(s)
+		}
+		*dst = &v
+		return nil
+	default:
+		return jsonTypeError[T](jsonKind(value))
+	}
+}
+
+// needsUnquote reports whether s contains escapes or invalid UTF-8.
+func needsUnquote(s []byte) bool {
+	for i, c := range s {
+		if c == '\\' {
+			return true
+		}
+		if c >= utf8.RuneSelf {
+			return bytes.IndexByte(s[i:], '\\') >= 0 || !utf8.Valid(s[i:])
+		}
+	}
+	return false
+}
+
+// DecodeJSONBool decodes a JSON boolean. The value must be valid JSON, as returned
+// by JSONObject.Value. JSON null sets *dst to nil.
+func DecodeJSONBool[T ~bool](value []byte, dst **T) error {
+	var v T
+	switch value[0] {
+	case 'n':
+		*dst = nil
+		return nil
+	case 't':
+		v = true
+	case 'f':
+		v = false
+	default:
+		return jsonTypeError[T](jsonKind(value))
+	}
+	*dst = &v
+	return nil
+}
+
+// DecodeJSONInt decodes a JSON number as a signed integer. The value must be valid
+// JSON, as returned by JSONObject.Value. JSON null sets *dst to nil.
+func DecodeJSONInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](value []byte, dst **T) error {
+	switch c := value[0]; {
+	case c == 'n':
+		*dst = nil
+		return nil
+	case c != '-' && (c < '0' || c > '9'):
+		return jsonTypeError[T](jsonKind(value))
+	}
+	neg := value[0] == '-'
+	digits := value
+	if neg {
+		digits = value[1:]
+	}
+	u, ok := parseJSONUint(digits)
+	var n int64
+	switch {
+	case !ok || (neg && u > 1<<63) || (!neg && u > math.MaxInt64):
+		return jsonTypeError[T]("number " + string(value))
+	case neg:
+		n = -int64(u)
+	default:
+		n = int64(u)
+	}
+	v := T(n)
+	if int64(v) != n {
+		return jsonTypeError[T]("number " + string(value))
+	}
+	*dst = &v
+	return nil
+}
+
+// DecodeJSONUint decodes a JSON number as an unsigned integer. The value must be
+// valid JSON, as returned by JSONObject.Value. JSON null sets *dst to nil.
+func DecodeJSONUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](value []byte, dst **T) error {
+	switch c := value[0]; {
+	case c == 'n':
+		*dst = nil
+		return nil
+	case c != '-' && (c < '0' || c > '9'):
+		return jsonTypeError[T](jsonKind(value))
+	}
+	u, ok := parseJSONUint(value)
+	v := T(u)
+	if !ok || uint64(v) != u {
+		return jsonTypeError[T]("number " + string(value))
+	}
+	*dst = &v
+	return nil
+}
+
+// parseJSONUint parses a string of decimal digits. It returns false if s contains
+// other characters or the number doesn't fit into 64 bits.
+func parseJSONUint(s []byte) (uint64, bool) {
+	var n uint64
+	for _, c := range s {
+		if c < '0' || c > '9' || n > math.MaxUint64/10 {
+			return 0, false
+		}
+		d := uint64(c - '0')
+		if n*10 > math.MaxUint64-d {
+			return 0, false
+		}
+		n = n*10 + d
+	}
+	return n, len(s) > 0
+}
+
+// DecodeJSONFloat decodes a JSON number as a floating-point number. The value must
+// be valid JSON, as returned by JSONObject.Value. JSON null sets *dst to nil.
+func DecodeJSONFloat[T ~float32 | ~float64](value []byte, dst **T) error {
+	switch c := value[0]; {
+	case c == 'n':
+		*dst = nil
+		return nil
+	case c != '-' && (c < '0' || c > '9'):
+		return jsonTypeError[T](jsonKind(value))
+	}
+	f, err := strconv.ParseFloat(string(value), reflect.TypeFor[T]().Bits())
+	if err != nil {
+		return jsonTypeError[T]("number " + string(value))
+	}
+	v := T(f)
+	*dst = &v
+	return nil
+}
+
+func jsonTypeError[T any](kind string) error {
+	return &json.UnmarshalTypeError{Value: kind, Type: reflect.TypeFor[T]()}
+}
+
+const hexDigits = "0123456789abcdef"
+
+// AppendJSONString appends s as a JSON string. Like package encoding/json, it escapes
+// the characters <, > and &, and replaces invalid UTF-8 by U+FFFD.
+func AppendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			// These are valid in JSON, but not in JavaScript.
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// AppendJSONFloat appends f as a JSON number. The bits argument is 32 for float32
+// values and 64 for float64. Like package encoding/json, it returns an error for NaN
+// and infinite values.
+func AppendJSONFloat(dst []byte, f float64, bits int) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return dst, &json.UnsupportedValueError{
+			Value: reflect.ValueOf(f),
+			Str:   strconv.FormatFloat(f, 'g', -1, bits),
+		}
+	}
+	// Large and small exponents use the 'e' format.
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst, nil
+}
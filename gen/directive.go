@@ -0,0 +1,112 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	directivePrefix = "//gencodec:generate"
+	defaultOutFile  = "gencodec.go"
+)
+
+// GenerateDirectives generates code for all types annotated with a
+// //gencodec:generate comment in the packages matched by cfg.Dir.
+func GenerateDirectives(cfg Config) ([]File, []Diagnostic, error) {
+	cfg.setDefaults()
+	pkgs, _, diags, err := loadPackages(&cfg)
+	if err != nil {
+		return nil, diags, err
+	}
+	var files []File
+	for _, pkg := range pkgs {
+		byFile, err := scanDirectives(cfg.FileSet, pkg)
+		if err != nil {
+			return nil, diags, err
+		}
+		for _, out := range slices.Sorted(maps.Keys(byFile)) {
+			code, warnings, err := cfg.generateFile(pkg.Types, byFile[out])
+			diags = append(diags, warnings...)
+			if err != nil {
+				return nil, diags, err
+			}
+			files = append(files, File{Path: out, Code: code})
+		}
+	}
+	return files, diags, nil
+}
+
+// scanDirectives finds all annotated types in a package and groups them by
+// output file path.
+func scanDirectives(fset *token.FileSet, pkg *packages.Package) (map[string][]TypeConfig, error) {
+	byFile := make(map[string][]TypeConfig)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				if doc == nil {
+					continue
+				}
+				for _, c := range doc.List {
+					if !isDirective(c.Text) {
+						continue
+					}
+					tc, out, err := parseDirective(c.Text)
+					if err != nil {
+						return nil, fmt.Errorf("%v: %v", fset.Position(c.Pos()), err)
+					}
+					tc.Name = ts.Name.Name
+					out = filepath.Join(pkg.Dir, out)
+					byFile[out] = append(byFile[out], tc)
+				}
+			}
+		}
+	}
+	return byFile, nil
+}
+
+func isDirective(text string) bool {
+	rest, ok := strings.CutPrefix(text, directivePrefix)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// parseDirective parses the options of a //gencodec:generate comment.
+func parseDirective(text string) (tc TypeConfig, out string, err error) {
+	out = defaultOutFile
+	for _, opt := range strings.Fields(strings.TrimPrefix(text, directivePrefix)) {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok || value == "" {
+			return tc, "", fmt.Errorf("invalid option %q in gencodec directive", opt)
+		}
+		switch key {
+		case "formats":
+			tc.Formats = strings.Split(value, ",")
+		case "override":
+			tc.FieldOverride = value
+		case "out":
+			out = value
+		default:
+			return tc, "", fmt.Errorf("unknown option %q in gencodec directive", key)
+		}
+	}
+	return tc, out, nil
+}
@@ -0,0 +1,476 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"slices"
+
+	. "github.com/garslo/gogen"
+)
+
+// Format describes an encoding format. For each format, gencodec generates a
+// marshaling and an unmarshaling method. Both methods convert between the original
+// type and an intermediate struct, which is encoded or decoded by the call given in
+// the method description.
+type Format interface {
+	// Name returns the name which selects the format in the -formats flag.
+	Name() string
+	// TagKey returns the struct tag key which renames fields in this format.
+	TagKey() string
+	// MarshalMethod describes the marshaling method.
+	MarshalMethod() Method
+	// UnmarshalMethod describes the unmarshaling method.
+	UnmarshalMethod() Method
+}
+
+// Method describes a generated marshaling method.
+//
+// Call is the Go expression which encodes or decodes the intermediate struct. In
+// Call and in the types of parameters and results, $v is a pointer to the
+// intermediate struct and $name refers to a parameter or to the package name of an
+// import. In unmarshaling methods, Call must evaluate to an error. In marshaling
+// methods, Call is the list of result values.
+//
+// Example for package encoding/json:
+//
+//	Method{
+//		Name:    "UnmarshalJSON",
+//		Params:  []Param{{Name: "input", Type: "[]byte"}},
+//		Results: []string{"error"},
+//		Imports: []ImportSpec{{Name: "json", Path: "encoding/json"}},
+//		Call:    "$json.Unmarshal($input, $v)",
+//	}
+type Method struct {
+	Name    string       // method name, e.g. "MarshalJSON"
+	Params  []Param      // method parameters
+	Results []string     // result types
+	Imports []ImportSpec // packages referenced by Call and types
+	Call    string       // encoding or decoding call
+}
+
+// Param is a parameter of a generated method.
+type Param struct {
+	Name string
+	Type string
+}
+
+// ImportSpec is a package imported by generated code.
+type ImportSpec struct {
+	Name string // package name
+	Path string // import path
+}
+
+// formatGenerator creates the methods of a format.
+type formatGenerator interface {
+	TagKey() string
+	imports() []ImportSpec
+	generate(mtyp *marshalerType) (marshal, unmarshal Function, err error)
+}
+
+var formats = make(map[string]formatGenerator)
+
+// RegisterFormat adds a format. It panics if a format with the same name exists.
+func RegisterFormat(f Format) {
+	registerFormat(f.Name(), methodFormat{f})
+}
+
+func registerFormat(name string, g formatGenerator) {
+	if _, ok := formats[name]; ok {
+		panic(fmt.Sprintf("format %q is already registered", name))
+	}
+	formats[name] = g
+}
+
+func lookupFormat(name string) (formatGenerator, error) {
+	g, ok := formats[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown format: %q", name)
+	}
+	return g, nil
+}
+
+// simpleFormat is a Format defined by its methods.
+type simpleFormat struct {
+	name, tag          string
+	marshal, unmarshal Method
+}
+
+func (f simpleFormat) Name() string            { return f.name }
+func (f simpleFormat) TagKey() string          { return f.tag }
+func (f simpleFormat) MarshalMethod() Method   { return f.marshal }
+func (f simpleFormat) UnmarshalMethod() Method { return f.unmarshal }
+
+// bytesFormat creates a format which encodes to a byte slice using the Marshal and
+// Unmarshal functions of the given package, like encoding/json.
+func bytesFormat(name, pkgName, pkgPath string) simpleFormat {
+	imports := []ImportSpec{{pkgName, pkgPath}}
+	return simpleFormat{
+		name: name,
+		tag:  name,
+		marshal: Method{
+			Results: []string{"[]byte", "error"},
+			Imports: imports,
+			Call:    "$" + pkgName + ".Marshal($v)",
+		},
+		unmarshal: Method{
+			Params:  []Param{{"input", "[]byte"}},
+			Results: []string{"error"},
+			Imports: imports,
+			Call:    "$" + pkgName + ".Unmarshal($input, $v)",
+		},
+	}
+}
+
+// valueFormat creates a format with the MarshalYAML/UnmarshalYAML convention.
+func valueFormat(name, methodName string) simpleFormat {
+	return simpleFormat{
+		name: name,
+		tag:  name,
+		marshal: Method{
+			Name:    "Marshal" + methodName,
+			Results: []string{"interface{}", "error"},
+			Call:    "$v, nil",
+		},
+		unmarshal: Method{
+			Name:    "Unmarshal" + methodName,
+			Params:  []Param{{"unmarshal", "func (interface{}) error"}},
+			Results: []string{"error"},
+			Call:    "$unmarshal($v)",
+		},
+	}
+}
+
+func withMethodNames(f simpleFormat, marshal, unmarshal string) simpleFormat {
+	f.marshal.Name, f.unmarshal.Name = marshal, unmarshal
+	return f
+}
+
+var (
+	jsonFormat = withMethodNames(bytesFormat("json", "json", "encoding/json"), "MarshalJSON", "UnmarshalJSON")
+
+	json2Format = simpleFormat{
+		name: "json2",
+		tag:  "json",
+		marshal: Method{
+			Name:    "MarshalJSONTo",
+			Params:  []Param{{"encoder", "*$jsontext.Encoder"}},
+			Results: []string{"error"},
+			Imports: []ImportSpec{{"json", "encoding/json/v2"}, {"jsontext", "encoding/json/jsontext"}},
+			Call:    "$json.MarshalEncode($encoder, $v)",
+		},
+		unmarshal: Method{
+			Name:    "UnmarshalJSONFrom",
+			Params:  []Param{{"decoder", "*$jsontext.Decoder"}},
+			Results: []string{"error"},
+			Imports: []ImportSpec{{"json", "encoding/json/v2"}, {"jsontext", "encoding/json/jsontext"}},
+			Call:    "$json.UnmarshalDecode($decoder, $v)",
+		},
+	}
+
+	msgpackFormat = simpleFormat{
+		name: "msgpack",
+		tag:  "msgpack",
+		marshal: Method{
+			Name:    "EncodeMsgpack",
+			Params:  []Param{{"encoder", "*$msgpack.Encoder"}},
+			Results: []string{"error"},
+			Imports: []ImportSpec{{"msgpack", msgpackPackage}},
+			Call:    "$encoder.Encode($v)",
+		},
+		unmarshal: Method{
+			Name:    "DecodeMsgpack",
+			Params:  []Param{{"decoder", "*$msgpack.Decoder"}},
+			Results: []string{"error"},
+			Imports: []ImportSpec{{"msgpack", msgpackPackage}},
+			Call:    "$decoder.Decode($v)",
+		},
+	}
+
+	rlpFormat = simpleFormat{
+		name: "rlp",
+		tag:  "rlp",
+		marshal: Method{
+			Name:    "EncodeRLP",
+			Params:  []Param{{"w", "$io.Writer"}},
+			Results: []string{"error"},
+			Imports: []ImportSpec{{"io", "io"}, {"rlp", rlpPackage}},
+			Call:    "$rlp.Encode($w, $v)",
+		},
+		unmarshal: Method{
+			Name:    "DecodeRLP",
+			Params:  []Param{{"s", "*$rlp.Stream"}},
+			Results: []string{"error"},
+			Imports: []ImportSpec{{"rlp", rlpPackage}},
+			Call:    "$s.Decode($v)",
+		},
+	}
+)
+
+func init() {
+	RegisterFormat(withMethodNames(bytesFormat("cbor", "cbor", cborPackage), "MarshalCBOR", "UnmarshalCBOR"))
+	RegisterFormat(withMethodNames(bytesFormat("bson", "bson", bsonPackage), "MarshalBSON", "UnmarshalBSON"))
+	RegisterFormat(json2Format)
+	RegisterFormat(msgpackFormat)
+	RegisterFormat(valueFormat("yaml", "YAML"))
+	RegisterFormat(valueFormat("toml", "TOML"))
+
+	// Formats with specialized generators.
+	registerFormat("json", customFormat{
+		tag:  "json",
+		imps: jsonFormat.marshal.Imports,
+		gen: func(mtyp *marshalerType) (Function, Function, error) {
+			if !mtyp.directJSON {
+				return methodFormat{jsonFormat}.generate(mtyp)
+			}
+			if err := checkDirectJSON(mtyp); err != nil {
+				return Function{}, Function{}, err
+			}
+			return genMarshalJSONDirect(mtyp), genUnmarshalJSONDirect(mtyp), nil
+		},
+	})
+	registerFormat("rlp", customFormat{
+		tag:  "rlp",
+		imps: append(rlpFormat.marshal.Imports, rlpFormat.unmarshal.Imports...),
+		gen: func(mtyp *marshalerType) (Function, Function, error) {
+			if err := checkRLP(mtyp); err != nil {
+				return Function{}, Function{}, err
+			}
+			if err := checkNoAlias(mtyp, "rlp"); err != nil {
+				return Function{}, Function{}, err
+			}
+			return methodFormat{rlpFormat}.generate(mtyp)
+		},
+	})
+	registerFormat("xml", customFormat{
+		tag:  "xml",
+		imps: []ImportSpec{{"xml", "encoding/xml"}},
+		gen: func(mtyp *marshalerType) (Function, Function, error) {
+			if mtyp.strict {
+				return Function{}, Function{}, fmt.Errorf("format xml doesn't support strict mode")
+			}
+			if err := checkXML(mtyp); err != nil {
+				return Function{}, Function{}, err
+			}
+			return genMarshalXML(mtyp), genUnmarshalXML(mtyp), nil
+		},
+	})
+	registerFormat("yaml3", customFormat{
+		tag:  "yaml",
+		imps: []ImportSpec{{"fmt", "fmt"}, {"yaml", yaml3Package}},
+		gen: func(mtyp *marshalerType) (Function, Function, error) {
+			if slices.Contains(mtyp.formats, "yaml") {
+				return Function{}, Function{}, fmt.Errorf("formats yaml and yaml3 can't be generated for the same type")
+			}
+			marshal, _ := methodFormat{valueFormat("yaml", "YAML")}.generateMethod(mtyp, false)
+			return marshal, genUnmarshalYAMLNode(mtyp), nil
+		},
+	})
+	registerFormat("env", customFormat{
+		tag:  "env",
+		imps: []ImportSpec{{"fmt", "fmt"}, {"strconv", "strconv"}, {"strings", "strings"}},
+		gen: func(mtyp *marshalerType) (Function, Function, error) {
+			// Only decoding is supported for environment variables.
+			unmarshal, err := genUnmarshalEnv(mtyp)
+			return Function{}, unmarshal, err
+		},
+	})
+	registerFormat("form", customFormat{
+		tag:  "form",
+		imps: []ImportSpec{{"fmt", "fmt"}, {"url", "net/url"}, {"strconv", "strconv"}},
+		gen: func(mtyp *marshalerType) (Function, Function, error) {
+			marshal, err := genEncodeValues(mtyp)
+			if err != nil {
+				return marshal, Function{}, err
+			}
+			unmarshal, err := genDecodeValues(mtyp)
+			return marshal, unmarshal, err
+		},
+	})
+	registerFormat("protowire", customFormat{
+		tag:  "proto",
+		imps: []ImportSpec{{"bytes", "bytes"}, {"math", "math"}, {"protowire", protowirePackage}},
+		gen: func(mtyp *marshalerType) (Function, Function, error) {
+			if err := checkNoAlias(mtyp, "protowire"); err != nil {
+				return Function{}, Function{}, err
+			}
+			if err := checkProto(mtyp); err != nil {
+				return Function{}, Function{}, err
+			}
+			marshal, err := genMarshalProto(mtyp)
+			if err != nil {
+				return marshal, Function{}, err
+			}
+			unmarshal, err := genUnmarshalProto(mtyp)
+			return marshal, unmarshal, err
+		},
+	})
+}
+
+// customFormat is a built-in format with a specialized generator.
+type customFormat struct {
+	tag  string
+	imps []ImportSpec
+	gen  func(mtyp *marshalerType) (marshal, unmarshal Function, err error)
+}
+
+func (f customFormat) TagKey() string {
+	return f.tag
+}
+
+func (f customFormat) imports() []ImportSpec {
+	return f.imps
+}
+
+func (f customFormat) generate(mtyp *marshalerType) (Function, Function, error) {
+	return f.gen(mtyp)
+}
+
+// methodFormat generates the methods described by a Format.
+type methodFormat struct {
+	Format
+}
+
+func (f methodFormat) imports() []ImportSpec {
+	return append(f.MarshalMethod().Imports, f.UnmarshalMethod().Imports...)
+}
+
+func (f methodFormat) generate(mtyp *marshalerType) (marshal, unmarshal Function, err error) {
+	if marshal, err = f.generateMethod(mtyp, false); err != nil {
+		return marshal, unmarshal, err
+	}
+	unmarshal, err = f.generateMethod(mtyp, true)
+	return marshal, unmarshal, err
+}
+
+// generateMethod creates a marshaling method from its description.
+func (f methodFormat) generateMethod(mtyp *marshalerType, isUnmarshal bool) (Function, error) {
+	var (
+		spec  = f.MarshalMethod()
+		m     = newMarshalMethod(mtyp, f.TagKey(), isUnmarshal)
+		recv  = m.receiver()
+		names = make(map[string]string)
+		fn    = Function{Receiver: recv, Name: spec.Name}
+	)
+	if isUnmarshal {
+		spec = f.UnmarshalMethod()
+		fn.Name = spec.Name
+	}
+	for _, imp := range spec.Imports {
+		names[imp.Name] = m.scope.parent.packageName(imp.Path)
+	}
+	for _, p := range spec.Params {
+		names[p.Name] = m.scope.newIdent(p.Name)
+	}
+	intertyp := m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+	v := Name(m.scope.newIdent("enc"))
+	if isUnmarshal {
+		v = Name(m.scope.newIdent("dec"))
+	}
+	names["v"] = "&" + v.Name
+
+	// Substitute names in the method description.
+	var expandErr error
+	expand := func(s string) string {
+		return os.Expand(s, func(name string) string {
+			ident, ok := names[name]
+			if !ok && expandErr == nil {
+				expandErr = fmt.Errorf("method %s: unknown name $%s", spec.Name, name)
+			}
+			return ident
+		})
+	}
+	for _, p := range spec.Params {
+		fn.Parameters = append(fn.Parameters, Type{Name: names[p.Name], TypeName: expand(p.Type)})
+	}
+	for _, r := range spec.Results {
+		fn.ReturnTypes = append(fn.ReturnTypes, Type{TypeName: expand(r)})
+	}
+	call, err := parseExprList(expand(spec.Call))
+	if expandErr != nil {
+		return fn, expandErr
+	}
+	if err != nil {
+		return fn, fmt.Errorf("method %s: invalid call %q: %v", spec.Name, spec.Call, err)
+	}
+
+	fn.Body = []Statement{
+		declStmt{intertyp},
+		Declare{Name: v.Name, TypeName: intertyp.Name},
+	}
+	if isUnmarshal {
+		if len(call) != 1 {
+			return fn, fmt.Errorf("method %s: call must be a single expression", spec.Name)
+		}
+		if !mtyp.strict {
+			fn.Body = append(fn.Body, errCheck(call[0]))
+		} else {
+			decode, err := m.strictDecode(f.Name(), names, v, call[0], expand)
+			if err != nil {
+				return fn, err
+			}
+			fn.Body = append(fn.Body, decode...)
+		}
+		fn.Body = append(fn.Body, m.unmarshalConversions(v, Name(recv.Name), f.TagKey())...)
+		fn.Body = append(fn.Body, m.unmarshalReturn())
+	} else {
+		fn.Body = append(fn.Body, m.marshalConversions(Name(recv.Name), v, f.TagKey())...)
+		fn.Body = append(fn.Body, Return{Values: call})
+	}
+	return fn, nil
+}
+
+// parseExprList parses a comma-separated list of expressions.
+func parseExprList(s string) ([]Expression, error) {
+	expr, err := parser.ParseExpr("_(" + s + ")")
+	if err != nil {
+		return nil, err
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || call.Ellipsis.IsValid() {
+		return nil, fmt.Errorf("not an expression list")
+	}
+	if fun, ok := call.Fun.(*ast.Ident); !ok || fun.Name != "_" {
+		return nil, fmt.Errorf("not an expression list")
+	}
+	list := make([]Expression, len(call.Args))
+	for i, arg := range call.Args {
+		list[i] = astExpr{clearPositions(arg)}
+	}
+	return list, nil
+}
+
+// astExpr is a parsed expression.
+type astExpr struct {
+	expr ast.Expr
+}
+
+func (e astExpr) Expression() ast.Expr {
+	return e.expr
+}
+
+// clearPositions removes the positions of a parsed expression, which are
+// meaningless in the generated file.
+func clearPositions(expr ast.Expr) ast.Expr {
+	posType := reflect.TypeOf(token.NoPos)
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		v := reflect.ValueOf(n).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).Type() == posType {
+				v.Field(i).SetInt(0)
+			}
+		}
+		return true
+	})
+	return expr
+}
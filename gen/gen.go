@@ -0,0 +1,991 @@
+// Copyright 2017 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+// Package gen implements the gencodec code generator. It can be used by programs that
+// generate code in-process instead of running the gencodec command. The documentation
+// of the command describes the supported struct tags, formats and field overrides.
+//
+// Additional formats can be added with RegisterFormat.
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+)
+
+// AllFormats are the formats which support all features of field overrides.
+var AllFormats = []string{"json", "yaml", "toml", "xml"}
+
+// codecPackage is imported by generated code that uses typed errors.
+const codecPackage = "github.com/fjl/gencodec/codec"
+
+// Packages used by generated code for third-party formats.
+const (
+	cborPackage    = "github.com/fxamacker/cbor/v2"
+	msgpackPackage = "github.com/vmihailenco/msgpack/v5"
+	rlpPackage     = "github.com/ethereum/go-ethereum/rlp"
+	bsonPackage    = "go.mongodb.org/mongo-driver/v2/bson"
+	yaml3Package   = "gopkg.in/yaml.v3"
+
+	protowirePackage = "google.golang.org/protobuf/encoding/protowire"
+)
+
+// Config is the configuration of the generator.
+type Config struct {
+	Dir           string       // input package directory
+	Type          string       // type to generate methods for
+	FieldOverride string       // name of struct type for field overrides
+	Types         []TypeConfig // more types, generated after Type
+	Formats       []string     // defaults to just "json", see Formats for supported names
+	AllErrors     bool         // report all missing and invalid fields on unmarshal
+	Validate      bool         // generate a Validate method
+	Strict        bool         // reject unknown keys on unmarshal
+	TypedErrors   bool         // use codec.FieldError for invalid fields
+	Mode          string       // JSON generation mode, "intermediate" (default) or "direct"
+	Importer      types.Importer
+	FileSet       *token.FileSet
+}
+
+// TypeConfig selects a type to generate methods for.
+type TypeConfig struct {
+	Name          string   // type to generate methods for
+	FieldOverride string   // name of struct type for field overrides
+	Formats       []string // overrides Config.Formats if non-nil
+}
+
+// typeConfigs returns all types selected by cfg.
+func (cfg *Config) typeConfigs() []TypeConfig {
+	var list []TypeConfig
+	if cfg.Type != "" {
+		list = append(list, TypeConfig{Name: cfg.Type, FieldOverride: cfg.FieldOverride})
+	}
+	return append(list, cfg.Types...)
+}
+
+func (cfg *Config) setDefaults() {
+	if cfg.FileSet == nil {
+		cfg.FileSet = token.NewFileSet()
+	}
+	if cfg.Importer == nil {
+		cfg.Importer = importer.Default()
+	}
+	if cfg.Formats == nil {
+		cfg.Formats = []string{"json"}
+	}
+}
+
+// Diagnostic is a problem found while loading the input package, such as a type
+// error or an embedded field which can't be promoted. Generation continues despite
+// such problems, but the output may be incomplete.
+type Diagnostic struct {
+	Pos string // position in the input, e.g. "file.go:10:2", may be empty
+	Msg string
+}
+
+func (d Diagnostic) String() string {
+	if d.Pos == "" {
+		return d.Msg
+	}
+	return d.Pos + ": " + d.Msg
+}
+
+// File is a generated file.
+type File struct {
+	Path string
+	Code []byte
+}
+
+// Generate creates the marshaling methods of the types selected by cfg.Type and
+// cfg.Types. It returns the source code of a Go file.
+func Generate(cfg Config) ([]byte, []Diagnostic, error) {
+	pkg, diags, err := cfg.load()
+	if err != nil {
+		return nil, diags, err
+	}
+	code, warnings, err := cfg.generateFile(pkg, cfg.typeConfigs())
+	return code, append(diags, warnings...), err
+}
+
+// GenerateWithProto is like Generate, but also creates the .proto file returned by
+// GenerateProto. The input package is loaded only once.
+func GenerateWithProto(cfg Config) (code, schema []byte, diags []Diagnostic, err error) {
+	pkg, diags, err := cfg.load()
+	if err != nil {
+		return nil, nil, diags, err
+	}
+	code, warnings, err := cfg.generateFile(pkg, cfg.typeConfigs())
+	diags = append(diags, warnings...)
+	if err != nil {
+		return nil, nil, diags, err
+	}
+	// The proto types are the same as in the Go file, so their
+	// diagnostics have already been reported.
+	schema, _, err = cfg.generateProto(pkg)
+	return code, schema, diags, err
+}
+
+// load loads the input package of cfg.
+func (cfg *Config) load() (*types.Package, []Diagnostic, error) {
+	cfg.setDefaults()
+	pkgs, tests, diags, err := loadPackages(cfg)
+	if err != nil {
+		return nil, diags, err
+	}
+	tcs := cfg.typeConfigs()
+	if len(tcs) == 0 {
+		return nil, diags, errors.New("no types specified")
+	}
+	// Types declared in _test.go files only exist in the test variant.
+	pkg := pkgs[0]
+	if test := tests[pkg.PkgPath]; test != nil && !declaresTypes(pkg.Types, tcs) {
+		pkg = test
+	}
+	return pkg.Types, diags, nil
+}
+
+// declaresTypes reports whether pkg declares all types and field overrides.
+func declaresTypes(pkg *types.Package, tcs []TypeConfig) bool {
+	for _, tc := range tcs {
+		for _, name := range []string{tc.Name, tc.FieldOverride} {
+			if name != "" && pkg.Scope().Lookup(name) == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Formats returns the names of all supported formats.
+func Formats() []string {
+	return slices.Sorted(maps.Keys(formats))
+}
+
+// generateFile creates a Go source file containing methods for the given types.
+// Problems which don't prevent generation are returned as diagnostics.
+func (cfg *Config) generateFile(pkg *types.Package, tcs []TypeConfig) (code []byte, diags []Diagnostic, err error) {
+	// Construct the marshaling types. They share a single file scope so
+	// imports and identifiers are resolved once for the whole output file.
+	scope := newFileScope(cfg.Importer, pkg)
+	mtyps, diags, err := cfg.marshalerTypes(scope, pkg, tcs)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	// Generate and format the output. Formatting uses goimports because it
+	// removes unused imports.
+	code, err = generate(scope, mtyps)
+	if err != nil {
+		return nil, diags, err
+	}
+	opt := &imports.Options{Comments: true, TabIndent: true, TabWidth: 8}
+	code, err = imports.Process("", code, opt)
+	if err != nil {
+		panic(fmt.Errorf("BUG: can't gofmt generated code: %v", err))
+	}
+	return code, diags, nil
+}
+
+// marshalerTypes creates the marshaling types for the given type configs. Fields
+// which are left out of the types are reported as diagnostics.
+func (cfg *Config) marshalerTypes(scope *fileScope, pkg *types.Package, tcs []TypeConfig) ([]*marshalerType, []Diagnostic, error) {
+	var (
+		mtyps []*marshalerType
+		diags []Diagnostic
+	)
+	for _, tc := range tcs {
+		typ, err := lookupStructType(pkg.Scope(), tc.Name)
+		if err != nil {
+			return nil, diags, fmt.Errorf("can't find %s in %q: %v", tc.Name, pkg.Path(), err)
+		}
+		formats := tc.Formats
+		if formats == nil {
+			formats = cfg.Formats
+		}
+		mtyp, warnings := newMarshalerType(cfg.FileSet, scope, typ, formats)
+		diags = append(diags, warnings...)
+		if tc.FieldOverride != "" {
+			otyp, err := lookupStructType(pkg.Scope(), tc.FieldOverride)
+			if err != nil {
+				return nil, diags, fmt.Errorf("can't find field replacement type %s: %v", tc.FieldOverride, err)
+			}
+			err = mtyp.loadOverrides(otyp)
+			if err != nil {
+				return nil, diags, err
+			}
+		}
+		if err := mtyp.loadOptions(); err != nil {
+			return nil, diags, err
+		}
+		mtyp.allErrors = cfg.AllErrors
+		mtyp.validate = cfg.Validate
+		if cfg.Strict {
+			mtyp.strict = true
+			scope.addImport("fmt")
+			scope.addImport("strings")
+		}
+		for _, format := range mtyp.formats {
+			g, err := lookupFormat(format)
+			if err != nil {
+				return nil, diags, err
+			}
+			for _, imp := range g.imports() {
+				scope.addPackage(imp.Path, imp.Name)
+			}
+		}
+		switch cfg.Mode {
+		case "", "intermediate":
+		case "direct":
+			mtyp.directJSON = true
+			scope.addPackage(codecPackage, "codec")
+			scope.addImport("strconv")
+			scope.addImport("strings")
+		default:
+			return nil, diags, fmt.Errorf("unknown mode %q", cfg.Mode)
+		}
+		if cfg.TypedErrors {
+			mtyp.typedErrors = true
+			scope.addPackage(codecPackage, "codec")
+		}
+		mtyps = append(mtyps, mtyp)
+	}
+	protoMessages := make(map[*types.TypeName]bool)
+	for _, mtyp := range mtyps {
+		if slices.Contains(mtyp.formats, "protowire") {
+			protoMessages[mtyp.orig.Obj()] = true
+		}
+	}
+	for _, mtyp := range mtyps {
+		mtyp.protoMessages = protoMessages
+	}
+	return mtyps, diags, nil
+}
+
+// GenerateProto creates a .proto file declaring the messages of all types selected
+// by cfg which use the protowire format.
+func GenerateProto(cfg Config) ([]byte, []Diagnostic, error) {
+	pkg, diags, err := cfg.load()
+	if err != nil {
+		return nil, diags, err
+	}
+	schema, warnings, err := cfg.generateProto(pkg)
+	return schema, append(diags, warnings...), err
+}
+
+// generateProto creates the .proto file for the selected types of pkg.
+func (cfg *Config) generateProto(pkg *types.Package) ([]byte, []Diagnostic, error) {
+	mtyps, diags, err := cfg.marshalerTypes(newFileScope(cfg.Importer, pkg), pkg, cfg.typeConfigs())
+	if err != nil {
+		return nil, diags, err
+	}
+	mtyps = slices.DeleteFunc(mtyps, func(mtyp *marshalerType) bool {
+		return !slices.Contains(mtyp.formats, "protowire")
+	})
+	if len(mtyps) == 0 {
+		return nil, diags, errors.New("no types with protowire format")
+	}
+	w := new(bytes.Buffer)
+	if err := writeProtoSchema(w, pkg, mtyps); err != nil {
+		return nil, diags, err
+	}
+	return w.Bytes(), diags, nil
+}
+
+// loadPackages loads the packages in cfg.Dir. If the directory ends in "/...",
+// all packages below it are loaded. Test variants of packages, which also contain
+// the _test.go files, are returned separately by import path. Errors in the loaded
+// packages are returned as diagnostics.
+func loadPackages(cfg *Config) ([]*packages.Package, map[string]*packages.Package, []Diagnostic, error) {
+	dir, pattern := cfg.Dir, "."
+	if filepath.Base(dir) == "..." {
+		dir, pattern = filepath.Dir(dir), "./..."
+	}
+	pcfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedDeps | packages.NeedImports | packages.NeedSyntax,
+		Tests: true,
+		Dir:   dir,
+		Fset:  cfg.FileSet,
+	}
+	ps, err := packages.Load(pcfg, pattern)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var (
+		list  []*packages.Package
+		tests = make(map[string]*packages.Package)
+		diags []Diagnostic
+	)
+	for _, p := range ps {
+		switch {
+		case p.ID == p.PkgPath && !strings.HasSuffix(p.PkgPath, ".test"):
+			list = append(list, p)
+			for _, e := range p.Errors {
+				diags = append(diags, Diagnostic{Pos: e.Pos, Msg: e.Msg})
+			}
+		case p.ID == p.PkgPath+" ["+p.PkgPath+".test]":
+			tests[p.PkgPath] = p
+		}
+	}
+	if len(list) == 0 {
+		return nil, nil, diags, fmt.Errorf("can't find go package in %s", cfg.Dir)
+	}
+	return list, tests, diags, nil
+}
+
+func generate(scope *fileScope, mtyps []*marshalerType) ([]byte, error) {
+	w := new(bytes.Buffer)
+	fmt.Fprint(w, "// Code generated by github.com/fjl/gencodec. DO NOT EDIT.\n\n")
+	fmt.Fprintln(w, "package", scope.pkg.Name())
+	fmt.Fprintln(w)
+	scope.writeImportDecl(w)
+	fmt.Fprintln(w)
+	for _, mtyp := range mtyps {
+		if mtyp.override != nil {
+			writeUseOfOverride(w, mtyp.override, mtyp.orig.TypeParams(), scope.qualify)
+		}
+	}
+	for _, mtyp := range mtyps {
+		writePatterns(w, mtyp)
+	}
+	for _, mtyp := range mtyps {
+		if err := generateType(w, mtyp); err != nil {
+			return nil, err
+		}
+	}
+	return w.Bytes(), nil
+}
+
+// generateType writes the marshaling methods of a single type.
+func generateType(w io.Writer, mtyp *marshalerType) error {
+	for _, format := range mtyp.formats {
+		g, err := lookupFormat(format)
+		if err != nil {
+			return err
+		}
+		genMarshal, genUnmarshal, err := g.generate(mtyp)
+		if err != nil {
+			return err
+		}
+		if genMarshal.Name != "" {
+			fmt.Fprintf(w, "// %s marshals as %s.", genMarshal.Name, formatName(format))
+			fmt.Fprintln(w)
+			writeFunction(w, mtyp.fs, genMarshal)
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "// %s unmarshals from %s.", genUnmarshal.Name, formatName(format))
+		fmt.Fprintln(w)
+		writeFunction(w, mtyp.fs, genUnmarshal)
+		fmt.Fprintln(w)
+	}
+	if mtyp.validate {
+		fmt.Fprint(w, "// Validate checks that required fields are set and all fields satisfy their constraints.")
+		fmt.Fprintln(w)
+		writeFunction(w, mtyp.fs, genValidate(mtyp))
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// formatName returns the name of a format for use in comments.
+func formatName(format string) string {
+	switch format {
+	case "json2":
+		return "JSON"
+	case "msgpack":
+		return "MessagePack"
+	case "yaml3":
+		return "YAML"
+	case "env":
+		return "environment variables"
+	case "form":
+		return "form values"
+	case "protowire":
+		return "protobuf"
+	default:
+		return strings.ToUpper(format)
+	}
+}
+
+func writeUseOfOverride(w io.Writer, n *types.Named, tparams *types.TypeParamList, qf types.Qualifier) {
+	name := types.TypeString(types.NewPointer(n), qf)
+	if n.TypeArgs().Len() == 0 {
+		fmt.Fprintf(w, "var _ = (%s)(nil)\n", name)
+	} else {
+		// Generic types can only be referenced with type arguments.
+		fmt.Fprintf(w, "func _%s() { var _ = (%s)(nil) }\n", typeParamDecl(tparams, qf), name)
+	}
+}
+
+// marshalerType represents the intermediate struct type used during marshaling.
+// This is the input data to all the Go code templates.
+type marshalerType struct {
+	name        string
+	Fields      []*marshalerField
+	fs          *token.FileSet
+	orig        *types.Named
+	override    *types.Named
+	scope       *fileScope
+	formats     []string
+	allErrors   bool // report all invalid fields in Unmarshal*
+	validate    bool // generate Validate method
+	strict      bool // reject unknown keys in Unmarshal*
+	typedErrors bool // use codec.FieldError for invalid fields
+	directJSON  bool // generate JSON methods without intermediate struct
+
+	// protoMessages contains the types which get protowire methods in the same file.
+	protoMessages map[*types.TypeName]bool
+}
+
+// marshalerField represents a field of the intermediate marshaling type.
+type marshalerField struct {
+	name     string
+	typ      types.Type
+	origTyp  types.Type
+	tag      string
+	function *types.Func  // map to a function instead of a field
+	embedded []*types.Var // embedded fields through which the field is promoted
+	promoted string       // name of the promoted field, if different from name
+	opts     fieldOptions // options from the gencodec tag
+}
+
+func newMarshalerType(fs *token.FileSet, scope *fileScope, typ *types.Named, formats []string) (*marshalerType, []Diagnostic) {
+	mtyp := &marshalerType{name: typ.Obj().Name(), fs: fs, orig: typ, scope: scope, formats: formats}
+	styp := typ.Underlying().(*types.Struct)
+	mtyp.scope.addReferences(styp)
+	for i := 0; i < typ.TypeParams().Len(); i++ {
+		mtyp.scope.addReferences(typ.TypeParams().At(i))
+	}
+
+	// Add packages which are always needed.
+	mtyp.scope.addImport("encoding/json")
+	mtyp.scope.addImport("errors")
+
+	c := &fieldCollector{fs: fs, pkg: scope.pkg, typ: typ, warned: make(map[*types.Var]bool)}
+	for _, f := range c.promote(styp, mtyp.tagKeys()) {
+		if len(f.embedded) > 0 {
+			mtyp.scope.addReferences(f.typ)
+		}
+		mtyp.Fields = append(mtyp.Fields, f)
+	}
+	return mtyp, c.diags
+}
+
+// tagKeys returns the struct tag keys of the formats of mtyp.
+func (mtyp *marshalerType) tagKeys() []string {
+	var keys []string
+	for _, format := range mtyp.formats {
+		if g, err := lookupFormat(format); err == nil && !slices.Contains(keys, g.TagKey()) {
+			keys = append(keys, g.TagKey())
+		}
+	}
+	return keys
+}
+
+// promotedField is a candidate for a field of the intermediate type.
+type promotedField struct {
+	*marshalerField
+	index   []int  // field indices along the embedding path
+	tagName string // name assigned by the tag of the format
+}
+
+// fieldCollector gathers the fields of a struct type, including promoted fields.
+// Each format has its own struct tag, so fields are promoted separately for the
+// tag key of each format.
+type fieldCollector struct {
+	fs     *token.FileSet
+	pkg    *types.Package
+	typ    *types.Named
+	warned map[*types.Var]bool // inaccessible fields which have been reported
+	diags  []Diagnostic        // fields which are left out
+
+	// state of the current tag key
+	key    string
+	seen   map[*types.Named]bool // embedded types on the current path
+	fields []promotedField
+}
+
+// warn adds a diagnostic for the given position.
+func (c *fieldCollector) warn(pos token.Pos, format string, args ...any) {
+	c.diags = append(c.diags, Diagnostic{Pos: c.fs.Position(pos).String(), Msg: fmt.Sprintf(format, args...)})
+}
+
+// promote returns the fields of the intermediate type. It contains the fields which
+// are promoted for any of the given tag keys. In the tags of the other keys, the
+// field is ignored using "-".
+func (c *fieldCollector) promote(styp *types.Struct, keys []string) []*marshalerField {
+	var (
+		byIndex = make(map[string]promotedField)
+		visible = make(map[string][]string) // tag keys by field index
+	)
+	for _, key := range keys {
+		c.key, c.fields = key, nil
+		c.seen = map[*types.Named]bool{c.typ: true}
+		c.collect(styp, nil, nil)
+		for _, pf := range c.dominantFields() {
+			id := fmt.Sprint(pf.index)
+			if _, ok := byIndex[id]; !ok {
+				byIndex[id] = pf
+			}
+			visible[id] = append(visible[id], key)
+		}
+	}
+	fields := slices.SortedFunc(maps.Values(byIndex), func(a, b promotedField) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	var result []*marshalerField
+	for _, pf := range fields {
+		f := pf.marshalerField
+		for _, key := range keys {
+			if !slices.Contains(visible[fmt.Sprint(pf.index)], key) {
+				f.tag = setTagValue(f.keepFallback(keys, key), key, "-")
+			}
+		}
+		result = append(result, f)
+	}
+	renameFields(result, keys)
+	return result
+}
+
+// collect appends all exported fields of styp to c.fields, descending into embedded
+// structs. Like in package encoding/json, embedded structs with a name in their tag
+// are not flattened, and exported fields of unexported embedded structs are
+// included. Embedded fields which cannot be accessed from c.pkg are skipped.
+func (c *fieldCollector) collect(styp *types.Struct, path []*types.Var, index []int) {
+	for i := 0; i < styp.NumFields(); i++ {
+		f := styp.Field(i)
+		tag := styp.Tag(i)
+		tagName := tagName(tag, c.key)
+		fieldIndex := append(index[:len(index):len(index)], i)
+		if f.Anonymous() && tagName == "" {
+			if inner, named := embeddedStruct(f.Type()); inner != nil {
+				if named != nil && c.seen[named] {
+					continue // cycle through pointer embedding
+				}
+				if !f.Exported() && f.Pkg() != c.pkg {
+					if !c.warned[f] {
+						c.warned[f] = true
+						c.warn(f.Pos(), "ignoring inaccessible embedded field %s", f.Name())
+					}
+					continue
+				}
+				if named != nil {
+					c.seen[named] = true
+				}
+				c.collect(inner, append(path[:len(path):len(path)], f), fieldIndex)
+				if named != nil {
+					delete(c.seen, named)
+				}
+				continue
+			}
+		}
+		if !f.Exported() {
+			continue
+		}
+		mf := &marshalerField{
+			name:     f.Name(),
+			typ:      f.Type(),
+			origTyp:  f.Type(),
+			tag:      tag,
+			embedded: path,
+		}
+		c.fields = append(c.fields, promotedField{mf, fieldIndex, tagName})
+	}
+}
+
+// fieldKey returns the name under which a field is encoded by the current format.
+func (c *fieldCollector) fieldKey(pf promotedField) string {
+	if pf.tagName != "" {
+		return pf.tagName
+	}
+	return defaultKey(c.key, pf.name)
+}
+
+// embeddedStruct returns the struct type of an embedded field, and the named type
+// declaring it.
+func embeddedStruct(typ types.Type) (*types.Struct, *types.Named) {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, _ := types.Unalias(typ).(*types.Named)
+	return underlying[*types.Struct](typ), named
+}
+
+// dominantFields resolves conflicts between fields, using the rules of package
+// encoding/json: among fields with the same encoded name, the one with the shallowest
+// depth is used. If there are multiple such fields, a field with a name in its tag
+// is preferred. If the conflict remains, all fields with the name are ignored and
+// reported as diagnostics.
+func (c *fieldCollector) dominantFields() []promotedField {
+	byKey := make(map[string][]promotedField)
+	for _, f := range c.fields {
+		if f.tagName != "-" {
+			byKey[c.fieldKey(f)] = append(byKey[c.fieldKey(f)], f)
+		}
+	}
+	var result []promotedField
+	for _, f := range c.fields {
+		if f.tagName == "-" {
+			result = append(result, f)
+			continue
+		}
+		group := byKey[c.fieldKey(f)]
+		if dominant, ok := dominantField(group); ok && dominant.marshalerField == f.marshalerField {
+			result = append(result, f)
+		} else if !ok && f.marshalerField == group[0].marshalerField {
+			c.warn(c.typ.Obj().Pos(), "ignoring ambiguous %s key %s of %s", c.key, c.fieldKey(f), c.typ.Obj().Name())
+		}
+	}
+	return result
+}
+
+// renameFields renames fields with clashing Go names. A field without a name in
+// its tags keeps its name because it is used as the key. Renamed fields get the
+// original key in their tags.
+func renameFields(fields []*marshalerField, keys []string) {
+	used := make(map[string]bool)
+	for _, f := range fields {
+		if !f.hasTagName(keys) {
+			used[f.name] = true
+		}
+	}
+	for _, f := range fields {
+		if !f.hasTagName(keys) {
+			continue
+		}
+		name := f.name
+		for i := 0; used[name]; i++ {
+			name = f.name + strconv.Itoa(i)
+		}
+		used[name] = true
+		if name == f.name {
+			continue
+		}
+		f.promoted, f.name = f.name, name
+		for _, key := range keys {
+			if key == "rlp" || key == "proto" {
+				continue
+			}
+			tag := f.keepFallback(keys, key)
+			name, opts, hasOpts := strings.Cut(tagValue(tag, key), ",")
+			if name != "" {
+				continue
+			}
+			name = defaultKey(key, f.origName())
+			if hasOpts {
+				name += "," + opts
+			}
+			f.tag = setTagValue(tag, key, name)
+		}
+	}
+}
+
+// hasTagName reports whether any of the tags of f assigns a name.
+func (f *marshalerField) hasTagName(keys []string) bool {
+	for _, key := range keys {
+		if tagName(f.tag, key) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// keepFallback returns the tag of f, prepared for changing the value of key.
+// Package cbor uses the json tag if there is no cbor tag. When the json tag is
+// changed, the cbor tag is set explicitly.
+func (f *marshalerField) keepFallback(keys []string, key string) string {
+	tag := reflect.StructTag(f.tag)
+	if key != "json" || !slices.Contains(keys, "cbor") {
+		return f.tag
+	}
+	if _, ok := tag.Lookup("cbor"); ok {
+		return f.tag
+	}
+	val, ok := tag.Lookup("json")
+	if !ok {
+		val = f.origName()
+	}
+	return setTagValue(f.tag, "cbor", val)
+}
+
+// defaultKey returns the key of a field without a name in its tag.
+func defaultKey(key, name string) string {
+	switch key {
+	case "yaml", "bson":
+		return strings.ToLower(name)
+	case "env":
+		return envName(name)
+	case "form":
+		return uncapitalize(name)
+	default:
+		return name
+	}
+}
+
+// tagValue returns the value of key in a struct tag. Like package cbor, it uses
+// the json tag if there is no cbor tag.
+func tagValue(tag, key string) string {
+	val, ok := reflect.StructTag(tag).Lookup(key)
+	if !ok && key == "cbor" {
+		return reflect.StructTag(tag).Get("json")
+	}
+	return val
+}
+
+// tagName returns the name assigned by the tag of key, which is "-" for ignored
+// fields. The tags of rlp and proto don't contain names.
+func tagName(tag, key string) string {
+	name, _, _ := strings.Cut(tagValue(tag, key), ",")
+	if (key == "rlp" || key == "proto") && name != "-" {
+		return ""
+	}
+	return name
+}
+
+// setTagValue sets the value of key in a struct tag.
+func setTagValue(tag, key, value string) string {
+	var (
+		pairs []string
+		found bool
+	)
+	for tag != "" {
+		// Parse the key and the quoted value like reflect.StructTag.Lookup.
+		tag = strings.TrimLeft(tag, " ")
+		i := strings.Index(tag, ":\"")
+		if i <= 0 {
+			break
+		}
+		name := tag[:i]
+		j := i + 2
+		for j < len(tag) && tag[j] != '"' {
+			if tag[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(tag) {
+			break
+		}
+		pair := tag[:j+1]
+		tag = tag[j+1:]
+		if name == key {
+			pair, found = key+":"+strconv.Quote(value), true
+		}
+		pairs = append(pairs, pair)
+	}
+	if !found {
+		pairs = append(pairs, key+":"+strconv.Quote(value))
+	}
+	return strings.Join(pairs, " ")
+}
+
+func dominantField(fields []promotedField) (promotedField, bool) {
+	depth := len(fields[0].embedded)
+	for _, f := range fields[1:] {
+		depth = min(depth, len(f.embedded))
+	}
+	var shallow, tagged []promotedField
+	for _, f := range fields {
+		if len(f.embedded) == depth {
+			shallow = append(shallow, f)
+			if f.tagName != "" {
+				tagged = append(tagged, f)
+			}
+		}
+	}
+	switch {
+	case len(shallow) == 1:
+		return shallow[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	default:
+		return promotedField{}, false
+	}
+}
+
+// findFunction returns a function with `name` that accepts no arguments
+// and returns a single value that is convertible to the given to type.
+func findFunction(typ *types.Named, name string, to types.Type) (*types.Func, types.Type) {
+	for i := 0; i < typ.NumMethods(); i++ {
+		fun := typ.Method(i)
+		if fun.Name() != name || !fun.Exported() {
+			continue
+		}
+		sign := fun.Type().(*types.Signature)
+		if sign.Params().Len() != 0 || sign.Results().Len() != 1 {
+			continue
+		}
+		if err := checkConvertible(sign.Results().At(0).Type(), to); err == nil {
+			return fun, sign.Results().At(0).Type()
+		}
+	}
+	return nil, nil
+}
+
+// loadOverrides sets field types of the intermediate marshaling type from
+// matching fields of otyp.
+func (mtyp *marshalerType) loadOverrides(otyp *types.Named) error {
+	// Generic override types are instantiated with the type parameters
+	// of the original type.
+	tparams := mtyp.orig.TypeParams()
+	if otyp.TypeParams().Len() > 0 {
+		if otyp.TypeParams().Len() != tparams.Len() {
+			return fmt.Errorf("%v: field override type %s must have %d type parameters", mtyp.fs.Position(otyp.Obj().Pos()), otyp.Obj().Name(), tparams.Len())
+		}
+		inst, err := types.Instantiate(nil, otyp, typeArgs(tparams), true)
+		if err != nil {
+			return fmt.Errorf("%v: invalid field override type: %v", mtyp.fs.Position(otyp.Obj().Pos()), err)
+		}
+		otyp = inst.(*types.Named)
+		for i := 0; i < tparams.Len(); i++ {
+			if c := tparams.At(i).Constraint(); !isUnnamedInterface(c) {
+				mtyp.scope.addReferences(c)
+			}
+		}
+	}
+	s := otyp.Underlying().(*types.Struct)
+	for i := 0; i < s.NumFields(); i++ {
+		of := s.Field(i)
+		if of.Anonymous() || !of.Exported() {
+			return fmt.Errorf("%v: field override type cannot have embedded or unexported fields", mtyp.fs.Position(of.Pos()))
+		}
+		f := mtyp.fieldByName(of.Name())
+		if f == nil {
+			// field not defined in original type, check if it maps to a suitable function and add it as an override
+			if fun, retType := findFunction(mtyp.orig, of.Name(), of.Type()); fun != nil {
+				f = &marshalerField{name: fun.Name(), origTyp: retType, typ: of.Type(), function: fun, tag: s.Tag(i)}
+				mtyp.Fields = append(mtyp.Fields, f)
+			} else {
+				return fmt.Errorf("%v: no matching field or function for %s in original type %s", mtyp.fs.Position(of.Pos()), of.Name(), mtyp.name)
+			}
+		}
+		if err := checkConvertible(of.Type(), f.origTyp); err != nil {
+			return fmt.Errorf("%v: invalid field override: %v", mtyp.fs.Position(of.Pos()), err)
+		}
+		f.typ = of.Type()
+	}
+	mtyp.scope.addReferences(s)
+	mtyp.override = otyp
+	return nil
+}
+
+// origName returns the name of the field in the original type.
+func (mf *marshalerField) origName() string {
+	if mf.promoted != "" {
+		return mf.promoted
+	}
+	return mf.name
+}
+
+func (mtyp *marshalerType) fieldByName(name string) *marshalerField {
+	for _, f := range mtyp.Fields {
+		if f.name == name || f.promoted == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// checkRLP verifies that only trailing fields of mtyp are optional in RLP.
+func checkRLP(mtyp *marshalerType) error {
+	var optional *marshalerField
+	for _, f := range mtyp.Fields {
+		opts := strings.Split(reflect.StructTag(f.tag).Get("rlp"), ",")
+		switch {
+		case slices.Contains(opts, "-"):
+			continue
+		case slices.Contains(opts, "optional"):
+			optional = f
+		case optional != nil:
+			return fmt.Errorf("field %s.%s: rlp field must be optional because preceding field %s is optional", mtyp.name, f.name, optional.name)
+		}
+	}
+	return nil
+}
+
+// checkXML verifies that package xml can encode the fields of mtyp. Maps are only
+// supported if they have a MarshalXML method.
+func checkXML(mtyp *marshalerType) error {
+	for _, f := range mtyp.Fields {
+		if isIgnored(f, "xml") || underlyingMap(f.typ) == nil {
+			continue
+		}
+		if obj, _, _ := types.LookupFieldOrMethod(f.typ, true, nil, "MarshalXML"); obj == nil {
+			return fmt.Errorf("field %s.%s: format xml doesn't support map type %s", mtyp.name, f.name, types.TypeString(f.typ, mtyp.scope.qualify))
+		}
+	}
+	return nil
+}
+
+// isRequired returns whether the field is required when decoding the given format.
+func (mf *marshalerField) isRequired(format string) bool {
+	return mf.opts.required && !mf.isSkipped(format)
+}
+
+// isSkipped reports whether the format's struct tag excludes the field. Fields
+// with json:"-" must be treated as optional. This also works for the other
+// supported formats.
+func (mf *marshalerField) isSkipped(format string) bool {
+	return strings.HasPrefix(reflect.StructTag(mf.tag).Get(format), "-")
+}
+
+// isXMLRawText reports whether the field holds the raw inner XML or a comment
+// of the element.
+func (mf *marshalerField) isXMLRawText() bool {
+	opts := strings.Split(reflect.StructTag(mf.tag).Get("xml"), ",")
+	return slices.Contains(opts[1:], "innerxml") || slices.Contains(opts[1:], "comment")
+}
+
+// encodedName returns the alternative field name assigned by the format's struct tag.
+func (mf *marshalerField) encodedName(format string) string {
+	if format == "rlp" {
+		return uncapitalize(mf.origName()) // rlp tags do not contain names
+	}
+	if format == "proto" {
+		return strings.ToLower(envName(mf.origName())) // proto tags contain field numbers
+	}
+	if format == "env" {
+		if val := reflect.StructTag(mf.tag).Get("env"); val != "" && val != "-" {
+			return val
+		}
+		return envName(mf.name)
+	}
+	val := tagValue(mf.tag, format)
+	if comma := strings.Index(val, ","); comma != -1 {
+		val = val[:comma]
+	}
+	if format == "xml" {
+		// Strip the namespace and parent elements.
+		if _, name, ok := strings.Cut(val, " "); ok {
+			val = name
+		}
+		val = val[strings.LastIndex(val, ">")+1:]
+	}
+	if val == "" || val == "-" {
+		switch format {
+		case "cbor", "msgpack", "bson":
+			// Use the key of the encoding package.
+			return defaultKey(format, mf.origName())
+		}
+		return uncapitalize(mf.origName())
+	}
+	return val
+}
+
+func uncapitalize(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,344 @@
+// Copyright 2017 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/diff"
+)
+
+// 'golden' tests. These tests compare the output code with the expected
+// code in internal/tests/*/output.go. The fixtures live in their own module
+// and the expected code can be updated by running
+//
+//    go generate ./...
+//
+// in the internal/tests directory. The fixtures also have their own tests, which
+// are run by TestFixtures.
+
+var testdata = filepath.Join("..", "internal", "tests")
+
+// TestFixtures runs the tests of the fixture module. They check that the golden
+// output actually works with the encoding packages.
+func TestFixtures(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping fixture tests in short mode")
+	}
+	cmd := exec.Command("go", "test", "./...")
+	cmd.Dir = testdata
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("fixture tests failed: %v\n%s", err, out)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	tests := []Config{
+		Config{Dir: "mapconv", Type: "X", FieldOverride: "Xo", Formats: []string{"json", "yaml", "toml"}},
+		Config{Dir: "sliceconv", Type: "X", FieldOverride: "Xo", Formats: AllFormats},
+		Config{Dir: "arrayconv", Type: "X", FieldOverride: "Xo", Formats: []string{"json"}},
+		Config{Dir: "nameclash", Type: "Y", FieldOverride: "yo", Formats: AllFormats},
+		Config{Dir: "omitempty", Type: "X", FieldOverride: "Xo", Formats: AllFormats},
+		Config{Dir: "reqfield", Type: "X", Formats: []string{"json"}},
+		Config{Dir: "ftypes", Type: "X", Formats: []string{"json"}},
+		Config{Dir: "funcoverride", Type: "Z", FieldOverride: "Zo", Formats: AllFormats},
+		Config{Dir: "ifaceoverride", Type: "Cfg", FieldOverride: "cfgOverride", Formats: AllFormats},
+		Config{Dir: "alias", Type: "X", FieldOverride: "xOverride", Formats: []string{"json"}},
+		Config{Dir: "generic", Type: "Envelope", FieldOverride: "envelopeMarshaling", Formats: []string{"json"}},
+		Config{Dir: "allerrors", Type: "X", FieldOverride: "Xo", Formats: []string{"json", "yaml"}, AllErrors: true},
+		Config{Dir: "typederrors", Type: "X", FieldOverride: "Xo", Formats: []string{"json", "yaml"}, AllErrors: true, TypedErrors: true},
+		Config{Dir: "direct", Types: []TypeConfig{{Name: "X", FieldOverride: "Xo"}, {Name: "Bench"}}, Formats: []string{"json"}, Mode: "direct"},
+		Config{Dir: "json2", Type: "X", FieldOverride: "Xo", Formats: []string{"json", "json2"}},
+		Config{Dir: "cbor", Type: "X", FieldOverride: "Xo", Formats: []string{"cbor"}},
+		Config{Dir: "msgpack", Type: "X", FieldOverride: "Xo", Formats: []string{"msgpack"}},
+		Config{Dir: "rlp", Type: "X", FieldOverride: "Xo", Formats: []string{"rlp"}},
+		Config{Dir: "xml", Types: []TypeConfig{{Name: "X", FieldOverride: "Xo"}, {Name: "Y"}}, Formats: []string{"xml"}},
+		Config{Dir: "bson", Type: "X", FieldOverride: "Xo", Formats: []string{"bson"}},
+		Config{Dir: "yaml3", Types: []TypeConfig{{Name: "X", FieldOverride: "Xo"}, {Name: "Y"}}, Formats: []string{"yaml3"}},
+		Config{Dir: "env", Type: "Config", FieldOverride: "configOverride", Formats: []string{"env"}},
+		Config{Dir: "form", Type: "Query", FieldOverride: "queryOverride", Formats: []string{"form"}},
+		Config{Dir: "protowire", Types: []TypeConfig{{Name: "X", FieldOverride: "Xo"}, {Name: "Y"}}, Formats: []string{"protowire"}},
+		Config{Dir: "customformat", Type: "X", FieldOverride: "Xo", Formats: []string{"gob"}},
+		Config{Dir: "defaults", Type: "Config", Formats: []string{"json", "yaml"}},
+		Config{Dir: "validate", Type: "X", Formats: []string{"json"}},
+		Config{Dir: "validatemethod", Type: "X", FieldOverride: "Xo", Formats: []string{"json"}, AllErrors: true, Validate: true},
+		Config{Dir: "keyalias", Type: "X", Formats: []string{"json", "yaml", "form"}},
+		Config{Dir: "embedded", Type: "X", FieldOverride: "xOverride", Formats: []string{"json", "yaml"}},
+		Config{Dir: "strict", Type: "X", FieldOverride: "Xo", Formats: []string{"json", "json2", "yaml", "toml", "form", "protowire", "cbor", "msgpack", "bson"}, Strict: true},
+		Config{Dir: "multitype", Types: []TypeConfig{{Name: "A", FieldOverride: "ao"}, {Name: "B"}}, Formats: []string{"json"}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.Dir, func(t *testing.T) {
+			t.Parallel()
+			runGoldenTest(t, test)
+		})
+	}
+}
+
+// gobFormat is added by the tests to check RegisterFormat. The encoding
+// functions are defined in package customformat.
+type gobFormat struct{}
+
+func init() {
+	RegisterFormat(gobFormat{})
+}
+
+func (gobFormat) Name() string   { return "gob" }
+func (gobFormat) TagKey() string { return "gob" }
+
+func (gobFormat) MarshalMethod() Method {
+	return Method{Name: "GobEncode", Results: []string{"[]byte", "error"}, Call: "encodeGob($v)"}
+}
+
+func (gobFormat) UnmarshalMethod() Method {
+	return Method{
+		Name:    "GobDecode",
+		Params:  []Param{{Name: "input", Type: "[]byte"}},
+		Results: []string{"error"},
+		Call:    "decodeGob($input, $v)",
+	}
+}
+
+func runGoldenTest(t *testing.T, cfg Config) {
+	cfg.Dir = filepath.Join(testdata, cfg.Dir)
+	want, err := os.ReadFile(filepath.Join(cfg.Dir, "output.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, _, err := Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := diff.Diff(string(want), string(code)); d != "" {
+		t.Errorf("output mismatch\n\n%s", d)
+	}
+}
+
+func TestGoldenProto(t *testing.T) {
+	cfg := Config{
+		Dir:     filepath.Join(testdata, "protowire"),
+		Types:   []TypeConfig{{Name: "X", FieldOverride: "Xo"}, {Name: "Y"}},
+		Formats: []string{"protowire"},
+	}
+	want, err := os.ReadFile(filepath.Join(cfg.Dir, "output.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, _, err := GenerateProto(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := diff.Diff(string(want), string(schema)); d != "" {
+		t.Errorf("output mismatch\n\n%s", d)
+	}
+}
+
+func TestGoldenDirectives(t *testing.T) {
+	cfg := Config{Dir: filepath.Join(testdata, "directive", "...")}
+	files, _, err := GenerateDirectives(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("got %d output files, want 1", len(files))
+	}
+	want, err := os.ReadFile(files[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := diff.Diff(string(want), string(files[0].Code)); d != "" {
+		t.Errorf("output mismatch\n\n%s", d)
+	}
+}
+
+func TestParseDirective(t *testing.T) {
+	tc, out, err := parseDirective("//gencodec:generate formats=json,yaml override=xo out=x_json.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TypeConfig{FieldOverride: "xo", Formats: []string{"json", "yaml"}}
+	if !reflect.DeepEqual(tc, want) || out != "x_json.go" {
+		t.Errorf("wrong result: %+v, out %q", tc, out)
+	}
+
+	for _, bad := range []string{
+		"//gencodec:generate formats",
+		"//gencodec:generate out=",
+		"//gencodec:generate foo=bar",
+	} {
+		if _, _, err := parseDirective(bad); err == nil {
+			t.Errorf("no error for %q", bad)
+		}
+	}
+}
+
+func TestParseFieldOptions(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want fieldOptions
+	}{
+		{"", fieldOptions{}},
+		{"required", fieldOptions{required: true}},
+		{"default=1", fieldOptions{def: "1", hasDef: true}},
+		{"default=a,b", fieldOptions{def: "a,b", hasDef: true}},
+		{"default=", fieldOptions{def: "", hasDef: true}},
+		{"min=1,max=64", fieldOptions{constraints: []constraint{{name: "min", text: "1"}, {name: "max", text: "64"}}}},
+		{"pattern=^[a-z]{1,3}$,len=2", fieldOptions{constraints: []constraint{{name: "pattern", text: "^[a-z]{1,3}$"}, {name: "len", text: "2"}}}},
+		{"required,oneof=a|b", fieldOptions{required: true, constraints: []constraint{{name: "oneof", text: "a|b"}}}},
+		{"required,alias=oldName", fieldOptions{required: true, alias: "oldName"}},
+	}
+	for _, test := range tests {
+		opts, err := parseFieldOptions(test.tag)
+		if err != nil {
+			t.Errorf("%q: %v", test.tag, err)
+			continue
+		}
+		if !reflect.DeepEqual(opts, test.want) {
+			t.Errorf("%q: wrong result %+v", test.tag, opts)
+		}
+	}
+
+	for _, bad := range []string{"foo", "required=1", "default", "required,default=1", "min", "min=1,min=2", "alias=", "alias=-", "alias=a,alias=b"} {
+		if _, err := parseFieldOptions(bad); err == nil {
+			t.Errorf("no error for %q", bad)
+		}
+	}
+}
+
+func TestDefaultErrors(t *testing.T) {
+	tests := map[string]string{
+		"badOverflow":  `field badOverflow.V: invalid default value "300": constant 300 overflows uint8`,
+		"badConstType": `field badConstType.V: invalid default value "DefaultPort": cannot use untyped int as bool`,
+		"badFieldType": `field badFieldType.V: invalid default value "1": type []int can't have a default value`,
+		"badRequired":  `field badRequired.V: required field can't have a default value`,
+	}
+	for typ, want := range tests {
+		cfg := Config{Dir: filepath.Join(testdata, "defaults"), Type: typ}
+		_, _, err := Generate(cfg)
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: wrong error %q\n want %q", typ, err, want)
+		}
+	}
+}
+
+func TestConstraintErrors(t *testing.T) {
+	tests := map[string]string{
+		"badMinString":  `field badMinString.V: invalid option min=1: min can only be used with numeric types`,
+		"badPatternInt": `field badPatternInt.V: invalid option pattern=[0-9]: pattern can only be used with string types`,
+		"badPattern":    "field badPattern.V: invalid option pattern=[0-9: error parsing regexp: missing closing ]: `[0-9`",
+		"badLenArray":   `field badLenArray.V: invalid option len=4: len can only be used with string, slice and map types`,
+		"badOneofValue": `field badOneofValue.V: invalid option oneof=1|256: constant 256 overflows uint8`,
+		"badMinMax":     `field badMinMax.V: min 5 is greater than max 1`,
+
+		"badOneofDuplicate": `field badOneofDuplicate.V: invalid option oneof=a|b|a: duplicate value a`,
+		"badOneofEqual":     `field badOneofEqual.V: invalid option oneof=maxRetries|10: duplicate value 10`,
+
+		"badDefaultMin":     `field badDefaultMin.V: default value "0" violates constraint min=1`,
+		"badDefaultOneof":   `field badDefaultOneof.V: default value "trace" violates constraint oneof=debug|info`,
+		"badDefaultPattern": `field badDefaultPattern.V: default value "ABC" violates constraint pattern=^[a-z]+$`,
+	}
+	for typ, want := range tests {
+		cfg := Config{Dir: filepath.Join(testdata, "validate"), Type: typ}
+		_, _, err := Generate(cfg)
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: wrong error %q\n want %q", typ, err, want)
+		}
+	}
+}
+
+func TestPatternNames(t *testing.T) {
+	cfg := Config{Dir: filepath.Join(testdata, "validate"), Types: []TypeConfig{{Name: "A"}, {Name: "AB"}}}
+	code, _, err := Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, decl := range []string{"var aBNamePattern = regexp.MustCompile(`^a$`)", "var aBNamePattern0 = regexp.MustCompile(`^b$`)"} {
+		if !strings.Contains(string(code), decl) {
+			t.Errorf("missing declaration %s", decl)
+		}
+	}
+}
+
+func TestTestFileType(t *testing.T) {
+	cfg := Config{Dir: filepath.Join(testdata, "multitype"), Type: "testOnly", Formats: []string{"json"}}
+	code, _, err := Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(code), "func (t testOnly) MarshalJSON() ([]byte, error)") {
+		t.Errorf("missing MarshalJSON method for testOnly:\n%s", code)
+	}
+}
+
+func TestStrictUnsupported(t *testing.T) {
+	tests := []struct {
+		dir, format string
+	}{
+		{"xml", "xml"},
+		{"customformat", "gob"},
+	}
+	for _, test := range tests {
+		cfg := Config{Dir: filepath.Join(testdata, test.dir), Type: "X", Formats: []string{test.format}, Strict: true}
+		_, _, err := Generate(cfg)
+		want := "format " + test.format + " doesn't support strict mode"
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: wrong error %q\n want %q", test.format, err, want)
+		}
+	}
+}
+
+func TestXMLMapUnsupported(t *testing.T) {
+	cfg := Config{Dir: filepath.Join(testdata, "mapconv"), Type: "X", FieldOverride: "Xo", Formats: []string{"xml"}}
+	_, _, err := Generate(cfg)
+	want := "field X.Map: format xml doesn't support map type map[replacedString]replacedInt"
+	if err == nil || err.Error() != want {
+		t.Errorf("wrong error %q\n want %q", err, want)
+	}
+}
+
+func TestEmbeddedWarnings(t *testing.T) {
+	cfg := Config{Dir: filepath.Join(testdata, "embedded"), Type: "ambiguous"}
+	_, diags, err := Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ignoring ambiguous json key Dup of ambiguous"
+	if len(diags) != 1 || diags[0].Msg != want || diags[0].Pos == "" {
+		t.Fatalf("wrong diagnostics %v\n want %q", diags, want)
+	}
+}
+
+func TestAliasErrors(t *testing.T) {
+	tests := map[string]string{
+		"badAliasKey":    `field badAliasKey.Name: alias "title" is the json name of field Title`,
+		"badAliasOwnKey": `field badAliasOwnKey.Name: alias "title" is the yaml name of the field`,
+		"badAliasTwice":  `field badAliasTwice.Title: alias "label" is also used by field Name`,
+	}
+	for typ, want := range tests {
+		cfg := Config{Dir: filepath.Join(testdata, "keyalias"), Type: typ, Formats: []string{"json", "yaml"}}
+		_, _, err := Generate(cfg)
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: wrong error %q\n want %q", typ, err, want)
+		}
+	}
+}
+
+func TestAliasUnsupported(t *testing.T) {
+	for _, format := range []string{"rlp", "protowire"} {
+		cfg := Config{Dir: filepath.Join(testdata, "keyalias"), Type: "X", Formats: []string{format}}
+		_, _, err := Generate(cfg)
+		want := "field X.Name: alias can't be used with format " + format
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: wrong error %q\n want %q", format, err, want)
+		}
+	}
+}
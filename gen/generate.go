@@ -0,0 +1,730 @@
+// Copyright 2017 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"io"
+	"reflect"
+	"strings"
+
+	. "github.com/garslo/gogen"
+)
+
+var (
+	NIL     = Name("nil")
+	intType = types.Universe.Lookup("int").Type()
+)
+
+type marshalMethod struct {
+	mtyp        *marshalerType
+	scope       *funcScope
+	isUnmarshal bool
+	format      string // struct tag key of the format
+	// cached identifiers for map, slice conversions
+	iterKey, iterVal Var
+	// errs collects decoding errors when mtyp.allErrors is set
+	errs Var
+	// errPos is the input node which provides the line and column for errors.
+	errPos Expression
+}
+
+func newMarshalMethod(mtyp *marshalerType, format string, isUnmarshal bool) *marshalMethod {
+	s := newFuncScope(mtyp.scope)
+	m := &marshalMethod{
+		mtyp:        mtyp,
+		scope:       newFuncScope(mtyp.scope),
+		isUnmarshal: isUnmarshal,
+		format:      format,
+		iterKey:     Name(s.newIdent("k")),
+		iterVal:     Name(s.newIdent("v")),
+	}
+	if isUnmarshal && mtyp.allErrors {
+		m.errs = Name(m.scope.newIdent("errs"))
+	}
+	return m
+}
+
+func writeFunction(w io.Writer, fs *token.FileSet, fn Function) {
+	printer.Fprint(w, fs, fn.Declaration())
+	fmt.Fprintln(w)
+}
+
+// genUnmarshalXML generates the UnmarshalXML method.
+func genUnmarshalXML(mtyp *marshalerType) Function {
+	var (
+		m        = newMarshalMethod(mtyp, "xml", true)
+		recv     = m.receiver()
+		decoder  = Name(m.scope.newIdent("decoder"))
+		start    = Name(m.scope.newIdent("start"))
+		intertyp = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		dec      = Name(m.scope.newIdent("dec"))
+		xml      = m.scope.parent.packageName("encoding/xml")
+	)
+	fn := Function{
+		Receiver:    recv,
+		Name:        "UnmarshalXML",
+		ReturnTypes: Types{{TypeName: "error"}},
+		Parameters: Types{
+			{Name: decoder.Name, TypeName: "*" + xml + ".Decoder"},
+			{Name: start.Name, TypeName: xml + ".StartElement"},
+		},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: dec.Name, TypeName: intertyp.Name},
+			errCheck(CallFunction{
+				Func:   Dotted{Receiver: decoder, Name: "DecodeElement"},
+				Params: []Expression{AddressOf{Value: dec}, AddressOf{Value: start}},
+			}),
+		},
+	}
+	fn.Body = append(fn.Body, m.unmarshalConversions(dec, Name(recv.Name), "xml")...)
+	fn.Body = append(fn.Body, m.unmarshalReturn())
+	return fn
+}
+
+// genMarshalXML generates the MarshalXML method.
+func genMarshalXML(mtyp *marshalerType) Function {
+	var (
+		m        = newMarshalMethod(mtyp, "xml", false)
+		recv     = m.receiver()
+		encoder  = Name(m.scope.newIdent("encoder"))
+		start    = Name(m.scope.newIdent("start"))
+		intertyp = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		enc      = Name(m.scope.newIdent("enc"))
+		xml      = m.scope.parent.packageName("encoding/xml")
+	)
+	fn := Function{
+		Receiver:    recv,
+		Name:        "MarshalXML",
+		ReturnTypes: Types{{TypeName: "error"}},
+		Parameters: Types{
+			{Name: encoder.Name, TypeName: "*" + xml + ".Encoder"},
+			{Name: start.Name, TypeName: xml + ".StartElement"},
+		},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: enc.Name, TypeName: intertyp.Name},
+		},
+	}
+	fn.Body = append(fn.Body, m.marshalConversions(Name(recv.Name), enc, "xml")...)
+	fn.Body = append(fn.Body, m.xmlElementName(start, enc)...)
+	fn.Body = append(fn.Body, Return{Values: []Expression{
+		CallFunction{
+			Func:   Dotted{Receiver: encoder, Name: "EncodeElement"},
+			Params: []Expression{AddressOf{Value: enc}, start},
+		},
+	}})
+	return fn
+}
+
+// xmlElementName creates the statements which set the element name from the
+// XMLName field. Package encoding/xml uses the name given by the caller of
+// MarshalXML otherwise.
+func (m *marshalMethod) xmlElementName(start, enc Var) []Statement {
+	for _, f := range m.mtyp.Fields {
+		if f.name != "XMLName" || len(f.embedded) > 0 {
+			continue
+		}
+		var (
+			xml       = m.scope.parent.packageName("encoding/xml")
+			startName = Dotted{Receiver: start, Name: "Name"}
+			encName   = Dotted{Receiver: enc, Name: "XMLName"}
+		)
+		tag, _, _ := strings.Cut(reflect.StructTag(f.tag).Get("xml"), ",")
+		ns, local, ok := strings.Cut(tag, " ")
+		if !ok {
+			ns, local = "", tag
+		}
+		if local != "" {
+			// The name in the tag takes precedence over the field value.
+			name := compositeLit{Type: Name(xml + ".Name"), Fields: []keyValue{{"Local", stringLit{local}}}}
+			if ns != "" {
+				name.Fields = append([]keyValue{{"Space", stringLit{ns}}}, name.Fields...)
+			}
+			return []Statement{Assign{Lhs: startName, Rhs: name}}
+		}
+		return []Statement{If{
+			Condition: NotEqual{Lhs: Dotted{Receiver: encName, Name: "Local"}, Rhs: stringLit{""}},
+			Body:      []Statement{Assign{Lhs: startName, Rhs: encName}},
+		}}
+	}
+	return nil
+}
+
+// genUnmarshalYAMLNode generates the UnmarshalYAML method for gopkg.in/yaml.v3.
+func genUnmarshalYAMLNode(mtyp *marshalerType) Function {
+	var (
+		m        = newMarshalMethod(mtyp, "yaml", true)
+		recv     = m.receiver()
+		node     = Name(m.scope.newIdent("node"))
+		intertyp = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		dec      = Name(m.scope.newIdent("dec"))
+		yaml     = m.scope.parent.packageName(yaml3Package)
+	)
+	m.errPos = node
+	fn := Function{
+		Receiver:    recv,
+		Name:        "UnmarshalYAML",
+		ReturnTypes: Types{{TypeName: "error"}},
+		Parameters:  Types{{Name: node.Name, TypeName: "*" + yaml + ".Node"}},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: dec.Name, TypeName: intertyp.Name},
+			errCheck(CallFunction{
+				Func:   Dotted{Receiver: node, Name: "Decode"},
+				Params: []Expression{AddressOf{Value: dec}},
+			}),
+		},
+	}
+	if mtyp.strict {
+		keys := Name(m.scope.newIdent("keys"))
+		fn.Body = append(fn.Body,
+			Declare{Name: keys.Name, TypeName: "map[string]" + yaml + ".Node"},
+			errCheck(CallFunction{
+				Func:   Dotted{Receiver: node, Name: "Decode"},
+				Params: []Expression{AddressOf{Value: keys}},
+			}),
+			m.checkKeys(keys, m.knownKeys(tagStrictKey("yaml", strings.ToLower)), false),
+		)
+	}
+	fn.Body = append(fn.Body, m.unmarshalConversions(dec, Name(recv.Name), "yaml")...)
+	fn.Body = append(fn.Body, m.unmarshalReturn())
+	return fn
+}
+
+func (m *marshalMethod) receiver() Receiver {
+	letter := strings.ToLower(m.mtyp.name[:1])
+	typ := m.mtyp.name + typeParamNames(m.mtyp.orig.TypeParams())
+	r := Receiver{Name: m.scope.newIdent(letter), Type: Name(typ)}
+	if m.isUnmarshal {
+		r.Type = Star{Value: r.Type}
+	}
+	return r
+}
+
+// decodedType returns the type of a field in the intermediate struct used for
+// unmarshaling. This is usually a type that can be checked for nil. Package
+// encoding/xml doesn't assign pointers for some fields, these keep their type.
+func (m *marshalMethod) decodedType(f *marshalerField) types.Type {
+	if m.format == "xml" {
+		if f.name == "XMLName" || (isString(f.typ) && f.isXMLRawText()) {
+			return f.typ
+		}
+	}
+	return ensureNilCheckable(f.typ)
+}
+
+// absentValue returns the value of an intermediate struct field when the
+// field is not present in the input, or nil if absence can't be detected.
+func absentValue(typ types.Type) Expression {
+	switch {
+	case types.Identical(typ, ensureNilCheckable(typ)):
+		return NIL
+	case isString(typ):
+		return stringLit{""}
+	default:
+		return nil
+	}
+}
+
+func (m *marshalMethod) intermediateType(name string) Struct {
+	s := Struct{Name: name}
+	for _, f := range m.mtyp.Fields {
+		if m.isUnmarshal && f.function != nil {
+			continue // fields generated from functions cannot be assigned on unmarshal
+		}
+		typ := f.typ
+		if m.isUnmarshal {
+			typ = m.decodedType(f)
+		} else if isNonEmptyInterface(f.origTyp) {
+			// Non-empty interface is left as-is for Marshal*, i.e. we let the
+			// interface value handle its own marshaling.
+			typ = f.origTyp
+		}
+		s.Fields = append(s.Fields, Field{
+			Name:     f.name,
+			TypeName: types.TypeString(typ, m.mtyp.scope.qualify),
+			Tag:      f.tag,
+		})
+		if m.isUnmarshal && f.opts.alias != "" {
+			s.Fields = append(s.Fields, Field{
+				Name:     f.opts.aliasField,
+				TypeName: types.TypeString(typ, m.mtyp.scope.qualify),
+				Tag:      m.mtyp.aliasTag(f),
+			})
+		}
+	}
+	return s
+}
+
+func (m *marshalMethod) unmarshalConversions(from, to Var, format string) (s []Statement) {
+	if m.mtyp.allErrors {
+		s = append(s, Declare{Name: m.errs.Name, TypeName: "[]error"})
+	}
+	for _, f := range m.mtyp.Fields {
+		if f.function != nil {
+			continue // fields generated from functions cannot be assigned
+		}
+
+		fieldName := f.encodedName(format)
+		accessFrom := Dotted{Receiver: from, Name: f.name}
+		accessTo := fieldAccess(to, f)
+		typ := m.decodedType(f)
+		s = append(s, m.mergeAlias(from, f, typ, fieldName)...)
+		conv := append(m.allocEmbedded(to, f), m.convert(accessFrom, accessTo, typ, f.origTyp, fieldName)...)
+		conv = m.checkConstraints(m.decodedValue(accessFrom, typ, f), f, fieldName, conv)
+		switch absent := absentValue(typ); {
+		case absent == nil:
+			// The field can't be checked for presence.
+			s = append(s, conv...)
+		case !f.isRequired(format):
+			present := If{Condition: NotEqual{Lhs: accessFrom, Rhs: absent}, Body: conv}
+			if def := f.defaultFor(format); def != nil {
+				setDefault := append(m.allocEmbedded(to, f), Assign{Lhs: accessTo, Rhs: def})
+				s = append(s, ifElseStmt{If: present, Else: setDefault})
+			} else {
+				s = append(s, present)
+			}
+		default:
+			cond := Equals{Lhs: accessFrom, Rhs: absent}
+			s = append(s, m.checkField(cond, m.missingFieldError(fieldName), conv)...)
+		}
+	}
+	return s
+}
+
+// mergeAlias creates statements which move the value of the alias of field f
+// into the field. It is an error if both are present.
+func (m *marshalMethod) mergeAlias(from Var, f *marshalerField, typ types.Type, fieldName string) []Statement {
+	absent := absentValue(typ)
+	if f.opts.alias == "" || absent == nil || f.isSkipped(m.format) {
+		return nil
+	}
+	var (
+		accessFrom  = Dotted{Receiver: from, Name: f.name}
+		accessAlias = Dotted{Receiver: from, Name: f.opts.aliasField}
+		err         = m.aliasError(fieldName, f.opts.alias)
+		move        = []Statement{Assign{Lhs: accessFrom, Rhs: accessAlias}}
+	)
+	return []Statement{If{
+		Condition: NotEqual{Lhs: accessAlias, Rhs: absent},
+		Body:      m.checkField(NotEqual{Lhs: accessFrom, Rhs: absent}, err, move),
+	}}
+}
+
+// checkField creates statements that report err if cond is true, and run the
+// conversion statements otherwise. Unless all errors are collected, decoding stops
+// at the first error.
+func (m *marshalMethod) checkField(cond, err Expression, conv []Statement) []Statement {
+	fail := If{Condition: cond, Body: []Statement{m.reportError(err)}}
+	if !m.mtyp.allErrors {
+		return append([]Statement{fail}, conv...)
+	}
+	return []Statement{ifElseStmt{If: fail, Else: conv}}
+}
+
+// reportError creates a statement which returns err, or adds it to the
+// collected errors if all errors are reported.
+func (m *marshalMethod) reportError(err Expression) Statement {
+	if !m.mtyp.allErrors {
+		return Return{Values: []Expression{err}}
+	}
+	return Assign{Lhs: m.errs, Rhs: CallFunction{Func: Name("append"), Params: []Expression{m.errs, err}}}
+}
+
+// decodedValue returns the value of field f in the intermediate struct, converted
+// to the type of the field if possible. The value is checked against the constraints
+// of f before it is assigned.
+func (m *marshalMethod) decodedValue(from Expression, typ types.Type, f *marshalerField) Expression {
+	if isPointer(typ) && !isPointer(f.origTyp) {
+		from = Star{Value: from}
+		typ = typ.(*types.Pointer).Elem()
+	}
+	if underlying[*types.Basic](typ) != nil && types.ConvertibleTo(typ, f.origTyp) {
+		return convertSimple(from, typ, f.origTyp, m.mtyp.scope.qualify)
+	}
+	return from
+}
+
+// checkConstraints creates statements which verify the constraints of field f
+// on the value v. The conversion statements conv run if v satisfies them. Without
+// conversion statements, all violated constraints are reported.
+func (m *marshalMethod) checkConstraints(v Expression, f *marshalerField, fieldName string, conv []Statement) []Statement {
+	s := conv
+	for i := len(f.opts.constraints) - 1; i >= 0; i-- {
+		c := f.opts.constraints[i]
+		err := m.constraintError(fieldName, c)
+		var cond Expression
+		switch c.name {
+		case "min":
+			cond = LessThan{Lhs: v, Rhs: c.values[0]}
+		case "max":
+			cond = GreaterThan{Lhs: v, Rhs: c.values[0]}
+		case "len":
+			cond = NotEqual{Lhs: lenCall(v), Rhs: Int(c.length)}
+		case "pattern":
+			var str Expression = v
+			if !types.Identical(f.origTyp, types.Typ[types.String]) {
+				str = CallFunction{Func: Name("string"), Params: []Expression{v}}
+			}
+			match := CallFunction{Func: Dotted{Receiver: Name(c.pattern), Name: "MatchString"}, Params: []Expression{str}}
+			cond = Not{Value: match}
+		case "oneof":
+			cases := []caseClause{{List: c.values}, {Body: []Statement{m.reportError(err)}}}
+			if !m.mtyp.allErrors || conv == nil {
+				s = append([]Statement{switchStmt{Tag: v, Cases: cases}}, s...)
+			} else {
+				cases[0].Body = s
+				s = []Statement{switchStmt{Tag: v, Cases: cases}}
+			}
+			continue
+		}
+		if conv == nil {
+			s = append(m.checkField(cond, err, nil), s...)
+		} else {
+			s = m.checkField(cond, err, s)
+		}
+	}
+	return s
+}
+
+// missingFieldError creates the error value for a missing required field.
+func (m *marshalMethod) missingFieldError(fieldName string) Expression {
+	if !m.mtyp.typedErrors {
+		return m.errorMessage(fmt.Sprintf("missing required field '%s' for %s", fieldName, m.mtyp.name))
+	}
+	return m.fieldErrorLit(stringLit{fieldName}, "ReasonMissing", nil)
+}
+
+// lengthError creates the error value for an array field with wrong input length.
+func (m *marshalMethod) lengthError(fieldName string, length int64) Expression {
+	if !m.mtyp.typedErrors {
+		return m.errorMessage(fmt.Sprintf("field '%s' has wrong length, need %d items", fieldName, length))
+	}
+	return m.fieldErrorLit(stringLit{fieldName}, "ReasonWrongLength", []keyValue{{"Length", Int(int(length))}})
+}
+
+// constraintError creates the error value for a field which violates constraint c.
+func (m *marshalMethod) constraintError(fieldName string, c constraint) Expression {
+	if !m.mtyp.typedErrors {
+		return m.errorMessage(fmt.Sprintf("field '%s' violates constraint %s", fieldName, c))
+	}
+	return m.fieldErrorLit(stringLit{fieldName}, "ReasonConstraint", []keyValue{{"Constraint", stringLit{c.String()}}})
+}
+
+// rangeError creates the error value for a decoded integer which doesn't fit into
+// the field type.
+func (m *marshalMethod) rangeError(fieldName string) Expression {
+	if !m.mtyp.typedErrors {
+		return m.errorMessage(fmt.Sprintf("value of field '%s' is out of range for %s", fieldName, m.mtyp.name))
+	}
+	return m.fieldErrorLit(stringLit{fieldName}, "ReasonOutOfRange", nil)
+}
+
+// aliasError creates the error value for a field which is present under its name
+// and its alias.
+func (m *marshalMethod) aliasError(fieldName, alias string) Expression {
+	if !m.mtyp.typedErrors {
+		return m.errorMessage(fmt.Sprintf("field '%s' and its alias '%s' are both set for %s", fieldName, alias, m.mtyp.name))
+	}
+	return m.fieldErrorLit(stringLit{fieldName}, "ReasonAlias", []keyValue{{"Alias", stringLit{alias}}})
+}
+
+// fieldErrorLit creates a codec.FieldError literal. The field name is
+// usually a string literal.
+func (m *marshalMethod) fieldErrorLit(fieldName Expression, reason string, extra []keyValue) Expression {
+	codec := Name(m.scope.parent.packageName(codecPackage))
+	lit := compositeLit{
+		Type: Dotted{Receiver: codec, Name: "FieldError"},
+		Fields: []keyValue{
+			{"Type", stringLit{m.mtyp.name}},
+			{"Field", fieldName},
+			{"Format", stringLit{m.format}},
+			{"Reason", Dotted{Receiver: codec, Name: reason}},
+		},
+	}
+	lit.Fields = append(lit.Fields, extra...)
+	if m.errPos != nil {
+		lit.Fields = append(lit.Fields,
+			keyValue{"Line", Dotted{Receiver: m.errPos, Name: "Line"}},
+			keyValue{"Column", Dotted{Receiver: m.errPos, Name: "Column"}},
+		)
+	}
+	return AddressOf{Value: lit}
+}
+
+// errorMessage creates an error value with the given message. The position of
+// m.errPos is prepended to the message if set.
+func (m *marshalMethod) errorMessage(msg string) Expression {
+	if m.errPos == nil {
+		return errorsNewCall(m.scope.parent, msg)
+	}
+	fmt := Name(m.scope.parent.packageName("fmt"))
+	return CallFunction{
+		Func: Dotted{Receiver: fmt, Name: "Errorf"},
+		Params: []Expression{
+			stringLit{"line %d, column %d: " + strings.ReplaceAll(msg, "%", "%%")},
+			Dotted{Receiver: m.errPos, Name: "Line"},
+			Dotted{Receiver: m.errPos, Name: "Column"},
+		},
+	}
+}
+
+// unmarshalReturn creates the final return statement of Unmarshal* methods.
+func (m *marshalMethod) unmarshalReturn() Statement {
+	if !m.mtyp.allErrors {
+		return Return{Values: []Expression{NIL}}
+	}
+	errors := m.scope.parent.packageName("errors")
+	join := variadicCall{
+		Func:   Dotted{Receiver: Name(errors), Name: "Join"},
+		Params: []Expression{m.errs},
+	}
+	return Return{Values: []Expression{join}}
+}
+
+func (m *marshalMethod) marshalConversions(from, to Var, fieldName string) (s []Statement) {
+	for _, f := range m.mtyp.Fields {
+		accessFrom := fieldAccess(from, f)
+		accessTo := Dotted{Receiver: to, Name: f.name}
+		var value Expression = accessFrom
+		if f.function != nil {
+			value = CallFunction{Func: accessFrom}
+		}
+		// Non-empty interface values are handled differently between Marshal* and Unmarshal*.
+		// The conversion is only applied in the Unmarshal* method.
+		// For Marshal*, we let the value handle its own encoding, i.e. conversion is skipped.
+		var fieldType = f.typ
+		if isNonEmptyInterface(f.origTyp) {
+			fieldType = f.origTyp
+		}
+		conv := m.convert(value, accessTo, f.origTyp, fieldType, fieldName)
+		s = append(s, guardEmbedded(from, f, conv)...)
+	}
+	return s
+}
+
+// fieldAccess creates the selector expression for field f of v.
+func fieldAccess(v Expression, f *marshalerField) Expression {
+	for _, e := range f.embedded {
+		v = Dotted{Receiver: v, Name: e.Name()}
+	}
+	if f.promoted != "" {
+		return Dotted{Receiver: v, Name: f.promoted}
+	}
+	return Dotted{Receiver: v, Name: f.name}
+}
+
+// guardEmbedded wraps stmts in nil checks for the embedded pointers
+// through which f is promoted.
+func guardEmbedded(v Expression, f *marshalerField, stmts []Statement) []Statement {
+	var ptrs []Expression
+	for _, e := range f.embedded {
+		v = Dotted{Receiver: v, Name: e.Name()}
+		if isPointer(e.Type()) {
+			ptrs = append(ptrs, v)
+		}
+	}
+	for i := len(ptrs) - 1; i >= 0; i-- {
+		stmts = []Statement{If{Condition: NotEqual{Lhs: ptrs[i], Rhs: NIL}, Body: stmts}}
+	}
+	return stmts
+}
+
+// allocEmbedded creates statements that allocate nil embedded pointers
+// through which f is promoted.
+func (m *marshalMethod) allocEmbedded(v Expression, f *marshalerField) (s []Statement) {
+	for _, e := range f.embedded {
+		v = Dotted{Receiver: v, Name: e.Name()}
+		if ptr, ok := e.Type().(*types.Pointer); ok {
+			elem := types.TypeString(ptr.Elem(), m.mtyp.scope.qualify)
+			s = append(s, If{
+				Condition: Equals{Lhs: v, Rhs: NIL},
+				Body:      []Statement{Assign{Lhs: v, Rhs: CallFunction{Func: Name("new"), Params: []Expression{Name(elem)}}}},
+			})
+		}
+	}
+	return s
+}
+
+func (m *marshalMethod) convert(from, to Expression, fromtyp, totyp types.Type, fieldName string) (s []Statement) {
+	// Remove pointer introduced by ensureNilCheckable during field building.
+	if isPointer(fromtyp) && !isPointer(totyp) && !isInterface(totyp) {
+		from = Star{Value: from}
+		fromtyp = fromtyp.(*types.Pointer).Elem()
+	} else if !isPointer(fromtyp) && isPointer(totyp) {
+		from = AddressOf{Value: from}
+		fromtyp = types.NewPointer(fromtyp)
+	}
+
+	qf := m.mtyp.scope.qualify
+	switch {
+	// Array -> slice (with [:] syntax)
+	case underlyingArray(fromtyp) != nil && underlyingSlice(totyp) != nil:
+		s = append(s, m.convertArrayToSlice(from, to, fromtyp, totyp)...)
+
+	// Slice -> array (with loop)
+	case underlyingSlice(fromtyp) != nil && underlyingArray(totyp) != nil:
+		s = append(s, m.convertSliceToArray(from, to, fromtyp, totyp, fieldName)...)
+
+	// Simple conversion `totyp(from)`
+	case types.ConvertibleTo(fromtyp, totyp):
+		s = append(s, Assign{Lhs: to, Rhs: convertSimple(from, fromtyp, totyp, qf)})
+
+	// slice/slice and map/map (with loop)
+	case underlyingSlice(fromtyp) != nil:
+		s = append(s, m.convertLoop(from, to, sliceKV(fromtyp), sliceKV(totyp))...)
+	case underlyingMap(fromtyp) != nil:
+		s = append(s, m.convertLoop(from, to, mapKV(fromtyp), mapKV(totyp))...)
+
+	default:
+		invalidConv(fromtyp, totyp, qf)
+	}
+	return s
+}
+
+type kvType struct {
+	Type      types.Type
+	Key, Elem types.Type
+}
+
+func mapKV(typ types.Type) kvType {
+	maptyp := underlyingMap(typ)
+	return kvType{typ, maptyp.Key(), maptyp.Elem()}
+}
+
+func sliceKV(typ types.Type) kvType {
+	slicetyp := underlyingSlice(typ)
+	return kvType{typ, intType, slicetyp.Elem()}
+}
+
+// convertLoop creates type conversion code between two slice/map types.
+func (m *marshalMethod) convertLoop(from, to Expression, fromTyp, toTyp kvType) (conv []Statement) {
+	if hasSideEffects(from) {
+		orig := from
+		from = Name(m.scope.newIdent("tmp"))
+		conv = []Statement{DeclareAndAssign{Lhs: from, Rhs: orig}}
+	}
+	// The actual conversion is a loop that assigns each element.
+	inner := []Statement{
+		Assign{Lhs: to, Rhs: makeCall(toTyp.Type, from, m.scope.parent.qualify)},
+		Range{
+			Key:        m.iterKey,
+			Value:      m.iterVal,
+			RangeValue: from,
+			Body: []Statement{Assign{
+				Lhs: Index{Value: to, Index: convertSimple(m.iterKey, fromTyp.Key, toTyp.Key, m.scope.parent.qualify)},
+				Rhs: convertSimple(m.iterVal, fromTyp.Elem, toTyp.Elem, m.scope.parent.qualify),
+			}},
+		},
+	}
+	// Preserve nil maps and slices when marshaling. This is not required for unmarshaling
+	// methods because the field is already nil-checked earlier.
+	if !m.isUnmarshal {
+		inner = []Statement{If{
+			Condition: NotEqual{Lhs: from, Rhs: NIL},
+			Body:      inner,
+		}}
+	}
+	return append(conv, inner...)
+}
+
+// arrayToSliceConv converts a slice value to an array.
+func (m *marshalMethod) convertArrayToSlice(from Expression, to Expression, fromtyp, totyp types.Type) (conv []Statement) {
+	if hasSideEffects(from) {
+		orig := from
+		from = Name(m.scope.newIdent("tmp"))
+		conv = []Statement{DeclareAndAssign{Lhs: from, Rhs: orig}}
+	}
+
+	fromEtype := underlyingArray(fromtyp).Elem()
+	toEtype := underlyingSlice(totyp).Elem()
+
+	if fromEtype == toEtype {
+		// For identical element types, we can just slice the array.
+		return append(conv, Assign{Lhs: to, Rhs: sliceExpr{Value: from}})
+	}
+
+	// For different element types, we need to convert each element.
+	return append(conv,
+		Assign{
+			Lhs: to,
+			Rhs: makeCall(totyp, from, m.scope.parent.qualify),
+		},
+		Range{
+			Key:        m.iterKey,
+			Value:      m.iterVal,
+			RangeValue: from,
+			Body: []Statement{Assign{
+				Lhs: Index{Value: to, Index: m.iterKey},
+				Rhs: convertSimple(m.iterVal, fromEtype, toEtype, m.scope.parent.qualify),
+			}},
+		},
+	)
+}
+
+// sliceToArrayConv converts an array value to a slice.
+func (m *marshalMethod) convertSliceToArray(from Expression, to Expression, fromtyp, totyp types.Type, fieldName string) (conv []Statement) {
+	if hasSideEffects(from) {
+		orig := from
+		from = Name(m.scope.newIdent("tmp"))
+		conv = []Statement{DeclareAndAssign{Lhs: from, Rhs: orig}}
+	}
+
+	fromEtype := underlyingSlice(fromtyp).Elem()
+	toArray := underlyingArray(totyp)
+	toEtype := toArray.Elem()
+
+	var copyConv []Statement
+	if fromEtype == toEtype {
+		// Copy can be used when element types are identical.
+		copyConv = append(copyConv, CallFunction{
+			Func: Name("copy"), Params: []Expression{
+				sliceExpr{Value: to},
+				from,
+			},
+		})
+	} else {
+		// Otherwise the conversion is a loop that assigns each element.
+		copyConv = append(copyConv, Range{
+			Key:        m.iterKey,
+			Value:      m.iterVal,
+			RangeValue: from,
+			Body: []Statement{Assign{
+				Lhs: Index{Value: to, Index: m.iterKey},
+				Rhs: convertSimple(m.iterVal, fromEtype, toEtype, m.scope.parent.qualify),
+			}},
+		})
+	}
+
+	// Check length of input slice matches the array size.
+	if m.isUnmarshal {
+		cond := NotEqual{Lhs: lenCall(from), Rhs: lenCall(to)}
+		return append(conv, m.checkField(cond, m.lengthError(fieldName, toArray.Len()), copyConv)...)
+	}
+	return append(conv, copyConv...)
+}
+
+func convertSimple(from Expression, fromtyp, totyp types.Type, qf types.Qualifier) Expression {
+	if types.AssignableTo(fromtyp, totyp) {
+		return from
+	}
+	if !types.ConvertibleTo(fromtyp, totyp) {
+		invalidConv(fromtyp, totyp, qf)
+	}
+	toname := types.TypeString(totyp, qf)
+	if isPointer(totyp) {
+		toname = "(" + toname + ")" // hack alert!
+	}
+	return CallFunction{Func: Name(toname), Params: []Expression{from}}
+}
+
+func invalidConv(from, to types.Type, qf types.Qualifier) {
+	panic(fmt.Errorf("BUG: invalid conversion %s -> %s", types.TypeString(from, qf), types.TypeString(to, qf)))
+}
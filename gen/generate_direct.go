@@ -0,0 +1,459 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	. "github.com/garslo/gogen"
+)
+
+// This file contains the generator for -mode=direct. In this mode, MarshalJSON
+// appends fields to a byte buffer directly instead of encoding an intermediate
+// struct, and UnmarshalJSON reads the members of the object using codec.JSONObject.
+// Strings, booleans and numbers are encoded and decoded by the functions of package
+// codec, other values use package encoding/json.
+
+// jsonKey returns the key of the field in JSON objects and the json tag options.
+// It returns ok == false for fields which are ignored by package encoding/json.
+func (mf *marshalerField) jsonKey() (key string, opts []string, ok bool) {
+	tag := reflect.StructTag(mf.tag).Get("json")
+	if tag == "-" {
+		return "", nil, false
+	}
+	name, optstr, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = mf.name
+	}
+	if optstr != "" {
+		opts = strings.Split(optstr, ",")
+	}
+	return name, opts, true
+}
+
+// encodedJSONType returns the type of the value encoded for a field.
+func (mf *marshalerField) encodedJSONType() types.Type {
+	// Like in the intermediate struct, non-empty interfaces encode themselves.
+	if isNonEmptyInterface(mf.origTyp) {
+		return mf.origTyp
+	}
+	return mf.typ
+}
+
+// checkDirectJSON verifies that all fields of mtyp can be handled by the direct
+// JSON generator.
+func checkDirectJSON(mtyp *marshalerType) error {
+	for _, f := range mtyp.Fields {
+		_, opts, ok := f.jsonKey()
+		if !ok {
+			continue
+		}
+		for _, opt := range opts {
+			switch opt {
+			case "string":
+				return fmt.Errorf("field %s.%s: json option ',string' is not supported with -mode=direct", mtyp.name, f.name)
+			case "omitzero":
+				if typ := f.encodedJSONType(); !hasIsZero(typ) && !types.Comparable(typ) && !isNilCheckable(typ) {
+					return fmt.Errorf("field %s.%s: json option ',omitzero' requires comparable type or IsZero method", mtyp.name, f.name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// genMarshalJSONDirect generates the MarshalJSON method for -mode=direct.
+func genMarshalJSONDirect(mtyp *marshalerType) Function {
+	var (
+		m    = newMarshalMethod(mtyp, "json", false)
+		recv = m.receiver()
+		buf  = Name(m.scope.newIdent("buf"))
+	)
+	fn := Function{
+		Receiver:    recv,
+		Name:        "MarshalJSON",
+		ReturnTypes: Types{{TypeName: "[]byte"}, {TypeName: "error"}},
+		Body: []Statement{
+			Declare{Name: buf.Name, TypeName: "[]byte"},
+		},
+	}
+	for _, f := range mtyp.Fields {
+		key, opts, ok := f.jsonKey()
+		if !ok {
+			continue
+		}
+		enc := m.encodeFieldDirect(Name(recv.Name), buf, f, key, opts)
+		fn.Body = append(fn.Body, guardEmbedded(Name(recv.Name), f, enc)...)
+	}
+	// Every field is prefixed by a comma. The first one is replaced by the
+	// opening brace of the object.
+	fn.Body = append(fn.Body,
+		If{
+			Condition: Equals{Lhs: lenCall(buf), Rhs: Int(0)},
+			Body: []Statement{Return{Values: []Expression{
+				CallFunction{Func: Name("[]byte"), Params: []Expression{stringLit{"{}"}}},
+				NIL,
+			}}},
+		},
+		Assign{Lhs: Index{Value: buf, Index: Int(0)}, Rhs: Name("'{'")},
+		Return{Values: []Expression{
+			CallFunction{Func: Name("append"), Params: []Expression{buf, Name("'}'")}},
+			NIL,
+		}},
+	)
+	return fn
+}
+
+// encodeFieldDirect creates the statements that append a field to buf.
+func (m *marshalMethod) encodeFieldDirect(recv, buf Var, f *marshalerField, key string, opts []string) (s []Statement) {
+	var (
+		value    = fieldAccess(recv, f)
+		valueTyp = f.encodedJSONType()
+		qf       = m.mtyp.scope.qualify
+	)
+	// Convert to the override type. The converted value is stored in a
+	// variable because encoding requires an addressable value.
+	if f.function != nil || !types.Identical(f.origTyp, valueTyp) {
+		from := value
+		if f.function != nil {
+			from = CallFunction{Func: value}
+		}
+		tmp := Name(m.scope.newIdent("val"))
+		s = append(s, Declare{Name: tmp.Name, TypeName: types.TypeString(valueTyp, qf)})
+		s = append(s, m.convert(from, tmp, f.origTyp, valueTyp, key)...)
+		value = tmp
+	}
+
+	keyJSON, _ := json.Marshal(key)
+	enc := []Statement{appendString(buf, ","+string(keyJSON)+":")}
+	enc = append(enc, m.encodeValueDirect(buf, value, valueTyp)...)
+	if cond := omitCondition(value, valueTyp, opts, qf); cond != nil {
+		return append(s, If{Condition: cond, Body: enc})
+	}
+	return append(s, enc...)
+}
+
+// encodeValueDirect creates the statements that append the JSON encoding of a
+// value to buf. Strings, booleans and numbers are encoded directly, all other
+// values are encoded by json.Marshal.
+func (m *marshalMethod) encodeValueDirect(buf Var, value Expression, typ types.Type) []Statement {
+	var (
+		qf      = m.mtyp.scope.qualify
+		strconv = Name(m.scope.parent.packageName("strconv"))
+		codec   = Name(m.scope.parent.packageName(codecPackage))
+		json    = Name(m.scope.parent.packageName("encoding/json"))
+		err     = Name("err")
+	)
+	if basic, ok := typ.Underlying().(*types.Basic); ok && !m.hasJSONMarshaler(typ) {
+		var call CallFunction
+		switch info := basic.Info(); {
+		case info&types.IsBoolean != 0:
+			call = CallFunction{
+				Func:   Dotted{Receiver: strconv, Name: "AppendBool"},
+				Params: []Expression{buf, convertSimple(value, typ, types.Typ[types.Bool], qf)},
+			}
+		case info&types.IsString != 0:
+			call = CallFunction{
+				Func:   Dotted{Receiver: codec, Name: "AppendJSONString"},
+				Params: []Expression{buf, convertSimple(value, typ, types.Typ[types.String], qf)},
+			}
+		case info&types.IsInteger != 0 && info&types.IsUnsigned != 0:
+			call = CallFunction{
+				Func:   Dotted{Receiver: strconv, Name: "AppendUint"},
+				Params: []Expression{buf, convertSimple(value, typ, types.Typ[types.Uint64], qf), Int(10)},
+			}
+		case info&types.IsInteger != 0:
+			call = CallFunction{
+				Func:   Dotted{Receiver: strconv, Name: "AppendInt"},
+				Params: []Expression{buf, convertSimple(value, typ, types.Typ[types.Int64], qf), Int(10)},
+			}
+		case info&types.IsFloat != 0:
+			// Encoding fails for NaN and infinity.
+			bits := 64
+			if basic.Kind() == types.Float32 {
+				bits = 32
+			}
+			call = CallFunction{
+				Func:   Dotted{Receiver: codec, Name: "AppendJSONFloat"},
+				Params: []Expression{buf, convertSimple(value, typ, types.Typ[types.Float64], qf), Int(bits)},
+			}
+			b := Name(m.scope.newIdent("b"))
+			return []Statement{ifElseStmt{
+				If: If{
+					Init:      assignMulti{Lhs: []Expression{b, err}, Rhs: call, Define: true},
+					Condition: NotEqual{Lhs: err, Rhs: NIL},
+					Body:      []Statement{Return{Values: []Expression{NIL, err}}},
+				},
+				Else: []Statement{Assign{Lhs: buf, Rhs: b}},
+			}}
+		}
+		if call.Func != nil {
+			return []Statement{Assign{Lhs: buf, Rhs: call}}
+		}
+	}
+
+	b := Name(m.scope.newIdent("b"))
+	return []Statement{ifElseStmt{
+		If: If{
+			Init: assignMulti{
+				Lhs:    []Expression{b, err},
+				Rhs:    CallFunction{Func: Dotted{Receiver: json, Name: "Marshal"}, Params: []Expression{AddressOf{Value: value}}},
+				Define: true,
+			},
+			Condition: NotEqual{Lhs: err, Rhs: NIL},
+			Body:      []Statement{Return{Values: []Expression{NIL, err}}},
+		},
+		Else: []Statement{Assign{
+			Lhs: buf,
+			Rhs: variadicCall{Func: Name("append"), Params: []Expression{buf, b}},
+		}},
+	}}
+}
+
+// decodeValueDirect creates the expression that decodes a JSON value into the field
+// dst of the intermediate struct. Strings, booleans and numbers are decoded by the
+// functions of package codec, all other values are decoded by json.Unmarshal.
+func (m *marshalMethod) decodeValueDirect(value, dst Expression, typ types.Type) Expression {
+	var (
+		codec = Name(m.scope.parent.packageName(codecPackage))
+		json  = Name(m.scope.parent.packageName("encoding/json"))
+		fn    string
+	)
+	if ptr, ok := typ.(*types.Pointer); ok && !m.hasJSONUnmarshaler(ptr.Elem()) {
+		if basic, ok := ptr.Elem().Underlying().(*types.Basic); ok {
+			switch info := basic.Info(); {
+			case info&types.IsBoolean != 0:
+				fn = "DecodeJSONBool"
+			case info&types.IsString != 0:
+				fn = "DecodeJSONString"
+			case info&types.IsInteger != 0 && info&types.IsUnsigned != 0:
+				fn = "DecodeJSONUint"
+			case info&types.IsInteger != 0:
+				fn = "DecodeJSONInt"
+			case info&types.IsFloat != 0:
+				fn = "DecodeJSONFloat"
+			}
+		}
+	}
+	if fn == "" {
+		return CallFunction{Func: Dotted{Receiver: json, Name: "Unmarshal"}, Params: []Expression{value, AddressOf{Value: dst}}}
+	}
+	return CallFunction{Func: Dotted{Receiver: codec, Name: fn}, Params: []Expression{value, AddressOf{Value: dst}}}
+}
+
+// hasJSONMarshaler reports whether values of typ implement json.Marshaler or
+// encoding.TextMarshaler.
+func (m *marshalMethod) hasJSONMarshaler(typ types.Type) bool {
+	for _, iface := range []*types.Interface{
+		marshalerInterface("MarshalJSON"),
+		marshalerInterface("MarshalText"),
+	} {
+		if types.Implements(typ, iface) || types.Implements(types.NewPointer(typ), iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasJSONUnmarshaler reports whether *typ implements json.Unmarshaler or
+// encoding.TextUnmarshaler.
+func (m *marshalMethod) hasJSONUnmarshaler(typ types.Type) bool {
+	return types.Implements(types.NewPointer(typ), unmarshalerInterface("UnmarshalJSON")) ||
+		m.hasTextUnmarshaler(typ)
+}
+
+// omitCondition returns the condition under which a field is encoded, according to
+// the omitempty and omitzero options. It returns nil if the field is always encoded.
+func omitCondition(v Expression, typ types.Type, opts []string, qf types.Qualifier) Expression {
+	var conds []Expression
+	for _, opt := range opts {
+		switch opt {
+		case "omitempty":
+			if c := notEmpty(v, typ); c != nil {
+				conds = append(conds, c)
+			}
+		case "omitzero":
+			conds = append(conds, notZero(v, typ, qf))
+		}
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+	cond := conds[0]
+	for _, c := range conds[1:] {
+		cond = binaryExpr{Op: token.LAND, X: cond, Y: c}
+	}
+	return cond
+}
+
+// notEmpty creates the check for a non-empty value as defined by package
+// encoding/json. It returns nil for types which are never empty.
+func notEmpty(v Expression, typ types.Type) Expression {
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		return notZeroBasic(v, u)
+	case *types.Slice, *types.Map, *types.Array:
+		return NotEqual{Lhs: lenCall(v), Rhs: Int(0)}
+	case *types.Pointer, *types.Interface:
+		return NotEqual{Lhs: v, Rhs: NIL}
+	default:
+		return nil
+	}
+}
+
+// notZero creates the check for a non-zero value as defined by package
+// encoding/json.
+func notZero(v Expression, typ types.Type, qf types.Qualifier) Expression {
+	if hasIsZero(typ) {
+		isZero := Not{Value: CallFunction{Func: Dotted{Receiver: v, Name: "IsZero"}}}
+		if isNilCheckable(typ) {
+			return binaryExpr{Op: token.LAND, X: NotEqual{Lhs: v, Rhs: NIL}, Y: isZero}
+		}
+		return isZero
+	}
+	if basic, ok := typ.Underlying().(*types.Basic); ok {
+		return notZeroBasic(v, basic)
+	}
+	if isNilCheckable(typ) {
+		return NotEqual{Lhs: v, Rhs: NIL}
+	}
+	zero := compositeLit{Type: Name(types.TypeString(typ, qf))}
+	return NotEqual{Lhs: v, Rhs: parenExpr{zero}}
+}
+
+func notZeroBasic(v Expression, typ *types.Basic) Expression {
+	switch info := typ.Info(); {
+	case info&types.IsBoolean != 0:
+		return v
+	case info&types.IsString != 0:
+		return NotEqual{Lhs: v, Rhs: stringLit{""}}
+	default:
+		return NotEqual{Lhs: v, Rhs: Int(0)}
+	}
+}
+
+// hasIsZero reports whether typ has an IsZero() bool method.
+func hasIsZero(typ types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, "IsZero")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	return types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool])
+}
+
+func isNilCheckable(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Slice, *types.Map, *types.Chan, *types.Signature:
+		return true
+	default:
+		return false
+	}
+}
+
+// genUnmarshalJSONDirect generates the UnmarshalJSON method for -mode=direct.
+func genUnmarshalJSONDirect(mtyp *marshalerType) Function {
+	var (
+		m        = newMarshalMethod(mtyp, "json", true)
+		recv     = m.receiver()
+		input    = Name(m.scope.newIdent("input"))
+		intertyp = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		dec      = Name(m.scope.newIdent("dec"))
+		obj      = Name(m.scope.newIdent("obj"))
+		key      = Name(m.scope.newIdent("key"))
+		value    = Name(m.scope.newIdent("value"))
+		name     = Name(m.scope.newIdent("name"))
+		err      = Name("err")
+		codec    = Name(m.scope.parent.packageName(codecPackage))
+		strings  = Name(m.scope.parent.packageName("strings"))
+	)
+	returnErr := If{Condition: NotEqual{Lhs: err, Rhs: NIL}, Body: []Statement{Return{Values: []Expression{err}}}}
+
+	// Create the switch which decodes object members.
+	var exact, folded []caseClause
+	decodeInto := func(k string, decode []Statement) {
+		exact = append(exact, caseClause{List: []Expression{stringLit{k}}, Body: decode})
+		equalFold := CallFunction{Func: Dotted{Receiver: strings, Name: "EqualFold"}, Params: []Expression{name, stringLit{k}}}
+		folded = append(folded, caseClause{List: []Expression{equalFold}, Body: decode})
+	}
+	decodeField := func(field string, typ types.Type) []Statement {
+		return []Statement{Assign{Lhs: err, Rhs: m.decodeValueDirect(value, Dotted{Receiver: dec, Name: field}, typ)}}
+	}
+	for _, f := range mtyp.Fields {
+		k, _, ok := f.jsonKey()
+		if !ok || (f.function != nil && !mtyp.strict) {
+			continue
+		}
+		if f.function != nil {
+			// The value has already been checked by the scanner.
+			decodeInto(k, nil)
+			continue
+		}
+		decodeInto(k, decodeField(f.name, m.decodedType(f)))
+		if f.opts.alias != "" {
+			decodeInto(f.opts.alias, decodeField(f.opts.aliasField, m.decodedType(f)))
+		}
+	}
+	// Keys are matched case-insensitively if there is no exact match. Unknown
+	// keys are skipped, or rejected in strict mode.
+	if mtyp.strict {
+		folded = append(folded, caseClause{Body: []Statement{Return{Values: []Expression{m.unknownKeyError(name)}}}})
+	}
+	members := switchStmt{
+		Tag: CallFunction{Func: Name("string"), Params: []Expression{key}},
+		Cases: append(exact, caseClause{Body: []Statement{switchStmt{
+			Init:  DeclareAndAssign{Lhs: name, Rhs: CallFunction{Func: Name("string"), Params: []Expression{key}}},
+			Cases: folded,
+		}}}),
+	}
+
+	fn := Function{
+		Receiver:    recv,
+		Name:        "UnmarshalJSON",
+		ReturnTypes: Types{{TypeName: "error"}},
+		Parameters:  Types{{Name: input.Name, TypeName: "[]byte"}},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: dec.Name, TypeName: intertyp.Name},
+			// JSON null leaves all fields unset.
+			DeclareAndAssign{Lhs: obj, Rhs: CallFunction{
+				Func:   Dotted{Receiver: codec, Name: "NewJSONObject"},
+				Params: []Expression{input, stringLit{mtyp.name}},
+			}},
+			For{
+				Condition: CallFunction{Func: Dotted{Receiver: obj, Name: "Next"}},
+				Body: []Statement{
+					Declare{Name: err.Name, TypeName: "error"},
+					DeclareAndAssign{Lhs: key, Rhs: CallFunction{Func: Dotted{Receiver: obj, Name: "Key"}}},
+					DeclareAndAssign{Lhs: value, Rhs: CallFunction{Func: Dotted{Receiver: obj, Name: "Value"}}},
+					members,
+					returnErr,
+				},
+			},
+			If{
+				Init:      DeclareAndAssign{Lhs: err, Rhs: CallFunction{Func: Dotted{Receiver: obj, Name: "Err"}}},
+				Condition: returnErr.Condition,
+				Body:      returnErr.Body,
+			},
+		},
+	}
+	fn.Body = append(fn.Body, m.unmarshalConversions(dec, Name(recv.Name), "json")...)
+	fn.Body = append(fn.Body, m.unmarshalReturn())
+	return fn
+}
+
+// appendString creates a statement like `buf = append(buf, "str"...)`.
+func appendString(buf Var, str string) Statement {
+	return Assign{Lhs: buf, Rhs: variadicCall{Func: Name("append"), Params: []Expression{buf, stringLit{str}}}}
+}
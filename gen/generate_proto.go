@@ -0,0 +1,656 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	. "github.com/garslo/gogen"
+)
+
+// protoKind is the protobuf type of an encoded value.
+type protoKind int
+
+const (
+	protoBool protoKind = iota
+	protoInt32
+	protoInt64
+	protoUint32
+	protoUint64
+	protoFloat
+	protoDouble
+	protoString
+	protoBytes
+	protoMessage
+)
+
+var protoKindNames = [...]string{
+	protoBool:   "bool",
+	protoInt32:  "int32",
+	protoInt64:  "int64",
+	protoUint32: "uint32",
+	protoUint64: "uint64",
+	protoFloat:  "float",
+	protoDouble: "double",
+	protoString: "string",
+	protoBytes:  "bytes",
+}
+
+// wireType returns the name of the protowire constant for the wire type of k.
+func (k protoKind) wireType() string {
+	switch k {
+	case protoFloat:
+		return "Fixed32Type"
+	case protoDouble:
+		return "Fixed64Type"
+	case protoString, protoBytes, protoMessage:
+		return "BytesType"
+	default:
+		return "VarintType"
+	}
+}
+
+// packable reports whether repeated values of kind k are stored as a packed list.
+func (k protoKind) packable() bool {
+	return k.wireType() != "BytesType"
+}
+
+// The largest field number allowed by protobuf.
+const maxProtoFieldNumber = 1<<29 - 1
+
+var (
+	byteSliceType = types.NewSlice(types.Typ[types.Byte])
+	errorType     = types.Universe.Lookup("error").Type()
+)
+
+// protoField describes how a field is stored in the protowire format.
+type protoField struct {
+	num      int
+	kind     protoKind
+	typ      types.Type // type of a single value, without pointer
+	repeated bool       // field is a slice of values
+	optional bool       // field is a pointer to a scalar value
+	pointer  bool       // messages are stored as pointers
+}
+
+// protoFieldNumber returns the field number assigned by the "proto" struct tag.
+func protoFieldNumber(f *marshalerField) (int, error) {
+	tag, _, _ := strings.Cut(reflect.StructTag(f.tag).Get("proto"), ",")
+	if tag == "" {
+		return 0, errors.New("missing proto field number")
+	}
+	num, err := strconv.Atoi(tag)
+	if err != nil || num < 1 || num > maxProtoFieldNumber || (num >= 19000 && num <= 19999) {
+		return 0, fmt.Errorf("invalid proto field number %q", tag)
+	}
+	return num, nil
+}
+
+// checkProto verifies the field numbers of a type.
+func checkProto(mtyp *marshalerType) error {
+	seen := make(map[int]*marshalerField)
+	for _, f := range mtyp.Fields {
+		if isIgnored(f, "proto") {
+			continue
+		}
+		num, err := protoFieldNumber(f)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+		}
+		if prev := seen[num]; prev != nil {
+			return fmt.Errorf("field %s.%s: proto field number %d is already used by %s", mtyp.name, f.name, num, prev.name)
+		}
+		seen[num] = f
+	}
+	return nil
+}
+
+// protoFieldOf determines the encoding of a field.
+func (mtyp *marshalerType) protoFieldOf(f *marshalerField) (pf protoField, err error) {
+	if pf.num, err = protoFieldNumber(f); err != nil {
+		return pf, err
+	}
+	typ := f.typ
+	switch {
+	case mtyp.isProtoMessage(typ):
+		pf.kind, pf.typ, pf.pointer = protoMessage, typ.(*types.Pointer).Elem(), true
+		return pf, nil
+	case isPointer(typ):
+		pf.optional = true
+		typ = typ.(*types.Pointer).Elem()
+	case underlyingSlice(typ) != nil && protoScalarKind(typ) != protoBytes:
+		pf.repeated = true
+		typ = underlyingSlice(typ).Elem()
+		if mtyp.isProtoMessage(typ) {
+			pf.kind, pf.typ, pf.pointer = protoMessage, typ.(*types.Pointer).Elem(), true
+			return pf, nil
+		}
+	}
+	pf.typ = typ
+	if mtyp.isProtoMessage(types.NewPointer(typ)) {
+		pf.kind = protoMessage
+		return pf, nil
+	}
+	if pf.kind = protoScalarKind(typ); pf.kind == protoMessage {
+		return pf, fmt.Errorf("type %s can't be encoded as protobuf", types.TypeString(f.typ, mtyp.scope.qualify))
+	}
+	return pf, nil
+}
+
+// protoScalarKind returns the protobuf scalar type of typ. It returns protoMessage
+// for types that aren't scalars.
+func protoScalarKind(typ types.Type) protoKind {
+	if slice := underlyingSlice(typ); slice != nil {
+		if types.Identical(slice.Elem().Underlying(), types.Typ[types.Byte]) {
+			return protoBytes
+		}
+		return protoMessage
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return protoMessage
+	}
+	switch basic.Kind() {
+	case types.Bool:
+		return protoBool
+	case types.Int8, types.Int16, types.Int32:
+		return protoInt32
+	case types.Int, types.Int64:
+		return protoInt64
+	case types.Uint8, types.Uint16, types.Uint32:
+		return protoUint32
+	case types.Uint, types.Uint64, types.Uintptr:
+		return protoUint64
+	case types.Float32:
+		return protoFloat
+	case types.Float64:
+		return protoDouble
+	case types.String:
+		return protoString
+	}
+	return protoMessage
+}
+
+// isProtoMessage reports whether typ has the methods generated for the protowire
+// format, or will have them after generating the current file. Only pointer types
+// can have both methods.
+func (mtyp *marshalerType) isProtoMessage(typ types.Type) bool {
+	ptr, ok := typ.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	if named, ok := ptr.Elem().(*types.Named); ok && mtyp.protoMessages[named.Obj()] {
+		return true
+	}
+	var (
+		input     = types.NewVar(0, nil, "input", byteSliceType)
+		output    = types.NewVar(0, nil, "", byteSliceType)
+		err       = types.NewVar(0, nil, "", errorType)
+		marshal   = types.NewSignatureType(nil, nil, nil, nil, types.NewTuple(output, err), false)
+		unmarshal = types.NewSignatureType(nil, nil, nil, types.NewTuple(input), types.NewTuple(err), false)
+		iface     = types.NewInterfaceType([]*types.Func{
+			types.NewFunc(0, nil, "MarshalProto", marshal),
+			types.NewFunc(0, nil, "UnmarshalProto", unmarshal),
+		}, nil)
+	)
+	return types.Implements(typ, iface.Complete())
+}
+
+// genMarshalProto generates the MarshalProto method.
+func genMarshalProto(mtyp *marshalerType) (Function, error) {
+	var (
+		m        = newMarshalMethod(mtyp, "proto", false)
+		recv     = m.receiver()
+		intertyp = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		enc      = Name(m.scope.newIdent("enc"))
+		b        = Name(m.scope.newIdent("b"))
+	)
+	fn := Function{
+		Receiver:    recv,
+		Name:        "MarshalProto",
+		ReturnTypes: Types{{TypeName: "[]byte"}, {TypeName: "error"}},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: enc.Name, TypeName: intertyp.Name},
+		},
+	}
+	fn.Body = append(fn.Body, m.marshalConversions(Name(recv.Name), enc, "proto")...)
+	fn.Body = append(fn.Body, Declare{Name: b.Name, TypeName: "[]byte"})
+	for _, f := range mtyp.Fields {
+		if isIgnored(f, "proto") {
+			continue
+		}
+		pf, err := mtyp.protoFieldOf(f)
+		if err != nil {
+			return fn, fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+		}
+		fn.Body = append(fn.Body, m.encodeProtoField(b, Dotted{Receiver: enc, Name: f.name}, f, pf)...)
+	}
+	fn.Body = append(fn.Body, Return{Values: []Expression{b, NIL}})
+	return fn, nil
+}
+
+// encodeProtoField creates the statements which append field f to the buffer b.
+func (m *marshalMethod) encodeProtoField(b Var, v Expression, f *marshalerField, pf protoField) []Statement {
+	switch {
+	case pf.repeated && pf.kind.packable():
+		var (
+			packed = Name(m.scope.newIdent("packed"))
+			elem   = Name(m.scope.newIdent("elem"))
+		)
+		return []Statement{If{
+			Condition: NotEqual{Lhs: lenCall(v), Rhs: Int(0)},
+			Body: []Statement{
+				Declare{Name: packed.Name, TypeName: "[]byte"},
+				Range{Value: elem, RangeValue: v, Body: []Statement{
+					Assign{Lhs: packed, Rhs: m.appendProtoScalar(packed, elem, pf)},
+				}},
+				Assign{Lhs: b, Rhs: m.appendProtoTag(b, pf.num, "BytesType")},
+				Assign{Lhs: b, Rhs: m.protowireCall("AppendBytes", b, packed)},
+			},
+		}}
+	case pf.repeated:
+		elem := Name(m.scope.newIdent("elem"))
+		body := m.encodeProtoValue(b, elem, pf)
+		if pf.pointer {
+			// nil elements are encoded as empty messages.
+			empty := If{
+				Condition: Equals{Lhs: elem, Rhs: NIL},
+				Body: []Statement{
+					Assign{Lhs: b, Rhs: m.appendProtoTag(b, pf.num, "BytesType")},
+					Assign{Lhs: b, Rhs: m.protowireCall("AppendBytes", b, NIL)},
+					continueStmt{},
+				},
+			}
+			body = append([]Statement{empty}, body...)
+		}
+		return []Statement{Range{Value: elem, RangeValue: v, Body: body}}
+	case pf.optional:
+		return []Statement{If{Condition: NotEqual{Lhs: v, Rhs: NIL}, Body: m.encodeProtoValue(b, Star{Value: v}, pf)}}
+	case pf.pointer:
+		return []Statement{If{Condition: NotEqual{Lhs: v, Rhs: NIL}, Body: m.encodeProtoValue(b, v, pf)}}
+	}
+	// Like proto3, zero values are not encoded unless the field is required.
+	// Fields with a default value must be encoded because the decoder would
+	// assign the default when the field is absent.
+	s := m.encodeProtoValue(b, v, pf)
+	if pf.kind != protoMessage && !f.isRequired("proto") && f.defaultFor("proto") == nil {
+		s = []Statement{If{Condition: notEmpty(v, pf.typ), Body: s}}
+	}
+	return s
+}
+
+// encodeProtoValue creates the statements which append a single value to b.
+func (m *marshalMethod) encodeProtoValue(b Var, v Expression, pf protoField) []Statement {
+	appendTag := Assign{Lhs: b, Rhs: m.appendProtoTag(b, pf.num, pf.kind.wireType())}
+	if pf.kind != protoMessage {
+		return []Statement{appendTag, Assign{Lhs: b, Rhs: m.appendProtoScalar(b, v, pf)}}
+	}
+	var (
+		mb  = Name(m.scope.newIdent("mb"))
+		err = Name("err")
+	)
+	return []Statement{ifElseStmt{
+		If: If{
+			Init: assignMulti{
+				Lhs:    []Expression{mb, err},
+				Rhs:    CallFunction{Func: Dotted{Receiver: v, Name: "MarshalProto"}},
+				Define: true,
+			},
+			Condition: NotEqual{Lhs: err, Rhs: NIL},
+			Body:      []Statement{Return{Values: []Expression{NIL, err}}},
+		},
+		Else: []Statement{appendTag, Assign{Lhs: b, Rhs: m.protowireCall("AppendBytes", b, mb)}},
+	}}
+}
+
+// appendProtoScalar creates the call which appends the scalar v to b.
+func (m *marshalMethod) appendProtoScalar(b, v Expression, pf protoField) Expression {
+	var (
+		qf   = m.mtyp.scope.qualify
+		math = Name(m.scope.parent.packageName("math"))
+	)
+	switch pf.kind {
+	case protoBool:
+		enc := m.protowireCall("EncodeBool", convertSimple(v, pf.typ, types.Typ[types.Bool], qf))
+		return m.protowireCall("AppendVarint", b, enc)
+	case protoFloat:
+		bits := CallFunction{Func: Dotted{Receiver: math, Name: "Float32bits"}, Params: []Expression{convertSimple(v, pf.typ, types.Typ[types.Float32], qf)}}
+		return m.protowireCall("AppendFixed32", b, bits)
+	case protoDouble:
+		bits := CallFunction{Func: Dotted{Receiver: math, Name: "Float64bits"}, Params: []Expression{convertSimple(v, pf.typ, types.Typ[types.Float64], qf)}}
+		return m.protowireCall("AppendFixed64", b, bits)
+	case protoString:
+		return m.protowireCall("AppendString", b, convertSimple(v, pf.typ, types.Typ[types.String], qf))
+	case protoBytes:
+		return m.protowireCall("AppendBytes", b, convertSimple(v, pf.typ, byteSliceType, qf))
+	default:
+		return m.protowireCall("AppendVarint", b, convertSimple(v, pf.typ, types.Typ[types.Uint64], qf))
+	}
+}
+
+func (m *marshalMethod) appendProtoTag(b Var, num int, wireType string) Expression {
+	protowire := Name(m.scope.parent.packageName(protowirePackage))
+	return m.protowireCall("AppendTag", b, Int(num), Dotted{Receiver: protowire, Name: wireType})
+}
+
+func (m *marshalMethod) protowireCall(name string, args ...Expression) Expression {
+	protowire := Name(m.scope.parent.packageName(protowirePackage))
+	return CallFunction{Func: Dotted{Receiver: protowire, Name: name}, Params: args}
+}
+
+// protoDecodeVars holds the identifiers used by UnmarshalProto.
+type protoDecodeVars struct {
+	input, num, wireType, n Var
+	v, elem, val            Var // declared in the switch cases
+}
+
+// genUnmarshalProto generates the UnmarshalProto method. Unknown fields and
+// fields with unexpected wire type are skipped.
+func genUnmarshalProto(mtyp *marshalerType) (Function, error) {
+	var (
+		m         = newMarshalMethod(mtyp, "proto", true)
+		recv      = m.receiver()
+		input     = Name(m.scope.newIdent("input"))
+		intertyp  = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		dec       = Name(m.scope.newIdent("dec"))
+		num       = Name(m.scope.newIdent("num"))
+		wireType  = Name(m.scope.newIdent("typ"))
+		n         = Name(m.scope.newIdent("n"))
+		protowire = Name(m.scope.parent.packageName(protowirePackage))
+		vars      = protoDecodeVars{
+			input:    input,
+			num:      num,
+			wireType: wireType,
+			n:        n,
+			v:        Name(m.scope.newIdent("v")),
+			elem:     Name(m.scope.newIdent("elem")),
+			val:      Name(m.scope.newIdent("val")),
+		}
+	)
+	fn := Function{
+		Receiver:    recv,
+		Name:        "UnmarshalProto",
+		ReturnTypes: Types{{TypeName: "error"}},
+		Parameters:  Types{{Name: input.Name, TypeName: "[]byte"}},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: dec.Name, TypeName: intertyp.Name},
+		},
+	}
+	var (
+		cases []caseClause
+		known []Expression // field numbers for strict mode
+	)
+	for _, f := range mtyp.Fields {
+		if isIgnored(f, "proto") {
+			continue
+		}
+		pf, err := mtyp.protoFieldOf(f)
+		if err != nil {
+			return fn, fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+		}
+		known = append(known, Int(pf.num))
+		if f.function == nil {
+			cases = append(cases, m.decodeProtoField(vars, Dotted{Receiver: dec, Name: f.name}, f, pf)...)
+		}
+	}
+	var skip []Statement
+	if mtyp.strict {
+		// Fields with a known number but the wrong wire type are skipped
+		// like in non-strict mode.
+		fail := []Statement{Return{Values: []Expression{m.unknownFieldNumberError(num)}}}
+		if len(known) == 0 {
+			skip = fail
+		} else {
+			skip = []Statement{switchStmt{Tag: num, Cases: []caseClause{{List: known}, {Body: fail}}}}
+		}
+	}
+	cases = append(cases, caseClause{Body: append(skip,
+		Assign{Lhs: n, Rhs: m.protowireCall("ConsumeFieldValue", num, wireType, input)},
+		m.protoParseCheck(n),
+		Assign{Lhs: input, Rhs: sliceExpr{Value: input, Low: n}},
+	)})
+	fn.Body = append(fn.Body, For{
+		Condition: GreaterThan{Lhs: lenCall(input), Rhs: Int(0)},
+		Body: []Statement{
+			assignMulti{
+				Lhs:    []Expression{num, wireType, n},
+				Rhs:    CallFunction{Func: Dotted{Receiver: protowire, Name: "ConsumeTag"}, Params: []Expression{input}},
+				Define: true,
+			},
+			m.protoParseCheck(n),
+			Assign{Lhs: input, Rhs: sliceExpr{Value: input, Low: n}},
+			switchStmt{Cases: cases},
+		},
+	})
+	fn.Body = append(fn.Body, m.unmarshalConversions(dec, Name(recv.Name), "proto")...)
+	fn.Body = append(fn.Body, m.unmarshalReturn())
+	return fn, nil
+}
+
+// decodeProtoField creates the switch cases which decode field f into dst.
+func (m *marshalMethod) decodeProtoField(vars protoDecodeVars, dst Expression, f *marshalerField, pf protoField) []caseClause {
+	var (
+		protowire = Name(m.scope.parent.packageName(protowirePackage))
+		input     = vars.input
+		v, n      = vars.v, vars.n
+		val       = vars.val
+		isField   = func(wt string) Expression {
+			return binaryExpr{
+				Op: token.LAND,
+				X:  Equals{Lhs: vars.num, Rhs: Int(pf.num)},
+				Y:  Equals{Lhs: vars.wireType, Rhs: Dotted{Receiver: protowire, Name: wt}},
+			}
+		}
+	)
+	// store creates the statements which assign the decoded value v.
+	store := func(v Expression) []Statement {
+		if pf.kind == protoMessage {
+			s := []Statement{
+				Declare{Name: val.Name, TypeName: types.TypeString(pf.typ, m.mtyp.scope.qualify)},
+				errCheck(CallFunction{Func: Dotted{Receiver: val, Name: "UnmarshalProto"}, Params: []Expression{v}}),
+			}
+			var result Expression = val
+			if pf.pointer || !pf.repeated {
+				result = AddressOf{Value: val}
+			}
+			if pf.repeated {
+				result = CallFunction{Func: Name("append"), Params: []Expression{dst, result}}
+			}
+			return append(s, Assign{Lhs: dst, Rhs: result})
+		}
+		value := m.protoScalarValue(v, pf)
+		var s []Statement
+		switch {
+		case pf.repeated:
+			s = []Statement{Assign{Lhs: dst, Rhs: CallFunction{Func: Name("append"), Params: []Expression{dst, value}}}}
+		case !isPointer(ensureNilCheckable(f.typ)):
+			s = []Statement{Assign{Lhs: dst, Rhs: value}}
+		case value == v:
+			s = []Statement{Assign{Lhs: dst, Rhs: AddressOf{Value: v}}}
+		default:
+			s = []Statement{
+				DeclareAndAssign{Lhs: val, Rhs: value},
+				Assign{Lhs: dst, Rhs: AddressOf{Value: val}},
+			}
+		}
+		if cond := m.protoRangeCheck(v, pf); cond != nil {
+			s = m.checkField(cond, m.rangeError(f.encodedName("proto")), s)
+		}
+		return s
+	}
+
+	consume := m.consumeProtoValue(input, v, n, pf)
+	single := caseClause{
+		List: []Expression{isField(pf.kind.wireType())},
+		Body: append(consume, store(v)...),
+	}
+	if !pf.repeated || !pf.kind.packable() {
+		return []caseClause{single}
+	}
+	// Repeated scalars can be stored as a packed list or as separate values.
+	var (
+		elem = vars.elem
+		loop = For{
+			Condition: GreaterThan{Lhs: lenCall(v), Rhs: Int(0)},
+			Body:      append(m.consumeProtoValue(v, elem, n, pf), store(elem)...),
+		}
+		packedBytes = []Statement{
+			assignMulti{Lhs: []Expression{v, n}, Rhs: m.protowireCall("ConsumeBytes", input), Define: true},
+			m.protoParseCheck(n),
+			Assign{Lhs: input, Rhs: sliceExpr{Value: input, Low: n}},
+		}
+	)
+	packed := caseClause{
+		List: []Expression{isField("BytesType")},
+		Body: append(packedBytes, loop),
+	}
+	return []caseClause{packed, single}
+}
+
+// consumeProtoValue creates the statements which read a single value from input
+// into the new variable v.
+func (m *marshalMethod) consumeProtoValue(input, v, n Var, pf protoField) []Statement {
+	var fn string
+	switch pf.kind {
+	case protoFloat:
+		fn = "ConsumeFixed32"
+	case protoDouble:
+		fn = "ConsumeFixed64"
+	case protoString:
+		fn = "ConsumeString"
+	case protoBytes, protoMessage:
+		fn = "ConsumeBytes"
+	default:
+		fn = "ConsumeVarint"
+	}
+	return []Statement{
+		assignMulti{Lhs: []Expression{v, n}, Rhs: m.protowireCall(fn, input), Define: true},
+		m.protoParseCheck(n),
+		Assign{Lhs: input, Rhs: sliceExpr{Value: input, Low: n}},
+	}
+}
+
+// protoScalarValue converts the value v returned by consumeProtoValue to the
+// field type.
+func (m *marshalMethod) protoScalarValue(v Expression, pf protoField) Expression {
+	var (
+		qf    = m.mtyp.scope.qualify
+		math  = Name(m.scope.parent.packageName("math"))
+		bytes = Name(m.scope.parent.packageName("bytes"))
+	)
+	switch pf.kind {
+	case protoBool:
+		return convertSimple(m.protowireCall("DecodeBool", v), types.Typ[types.Bool], pf.typ, qf)
+	case protoFloat:
+		f := CallFunction{Func: Dotted{Receiver: math, Name: "Float32frombits"}, Params: []Expression{v}}
+		return convertSimple(f, types.Typ[types.Float32], pf.typ, qf)
+	case protoDouble:
+		f := CallFunction{Func: Dotted{Receiver: math, Name: "Float64frombits"}, Params: []Expression{v}}
+		return convertSimple(f, types.Typ[types.Float64], pf.typ, qf)
+	case protoString:
+		return convertSimple(v, types.Typ[types.String], pf.typ, qf)
+	case protoBytes:
+		// The input buffer may be reused by the caller.
+		clone := CallFunction{Func: Dotted{Receiver: bytes, Name: "Clone"}, Params: []Expression{v}}
+		return convertSimple(clone, byteSliceType, pf.typ, qf)
+	default:
+		return convertSimple(v, types.Typ[types.Uint64], pf.typ, qf)
+	}
+}
+
+// protoRangeCheck returns the condition under which the varint v doesn't fit into
+// the integer type of the field. It returns nil if no check is needed.
+func (m *marshalMethod) protoRangeCheck(v Expression, pf protoField) Expression {
+	basic, ok := pf.typ.Underlying().(*types.Basic)
+	if !ok || pf.kind == protoBool || basic.Info()&types.IsInteger == 0 {
+		return nil
+	}
+	var (
+		math  = Name(m.scope.parent.packageName("math"))
+		limit = func(name string) Expression { return Dotted{Receiver: math, Name: name} }
+		x     = CallFunction{Func: Name("int64"), Params: []Expression{v}}
+	)
+	signed := func(min, max string) Expression {
+		return binaryExpr{
+			Op: token.LOR,
+			X:  LessThan{Lhs: x, Rhs: limit(min)},
+			Y:  GreaterThan{Lhs: x, Rhs: limit(max)},
+		}
+	}
+	switch basic.Kind() {
+	case types.Int8:
+		return signed("MinInt8", "MaxInt8")
+	case types.Int16:
+		return signed("MinInt16", "MaxInt16")
+	case types.Int32:
+		return signed("MinInt32", "MaxInt32")
+	case types.Int:
+		return signed("MinInt", "MaxInt")
+	case types.Uint8:
+		return GreaterThan{Lhs: v, Rhs: limit("MaxUint8")}
+	case types.Uint16:
+		return GreaterThan{Lhs: v, Rhs: limit("MaxUint16")}
+	case types.Uint32:
+		return GreaterThan{Lhs: v, Rhs: limit("MaxUint32")}
+	case types.Uint, types.Uintptr:
+		return GreaterThan{Lhs: v, Rhs: limit("MaxUint")}
+	}
+	return nil
+}
+
+// protoParseCheck creates the check for a negative length returned by the
+// protowire.Consume* functions.
+func (m *marshalMethod) protoParseCheck(n Var) Statement {
+	return If{
+		Condition: LessThan{Lhs: n, Rhs: Int(0)},
+		Body:      []Statement{Return{Values: []Expression{m.protowireCall("ParseError", n)}}},
+	}
+}
+
+// writeProtoSchema writes a .proto file declaring a message for each type.
+func writeProtoSchema(w io.Writer, pkg *types.Package, mtyps []*marshalerType) error {
+	fmt.Fprint(w, "// Code generated by github.com/fjl/gencodec. DO NOT EDIT.\n\n")
+	fmt.Fprintf(w, "syntax = \"proto3\";\n\npackage %s;\n", pkg.Name())
+	for _, mtyp := range mtyps {
+		if err := checkProto(mtyp); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\nmessage %s {\n", mtyp.name)
+		for _, f := range mtyp.Fields {
+			if isIgnored(f, "proto") {
+				continue
+			}
+			pf, err := mtyp.protoFieldOf(f)
+			if err != nil {
+				return fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+			}
+			var typeName string
+			if pf.kind == protoMessage {
+				typeName = pf.typ.(*types.Named).Obj().Name()
+			} else {
+				typeName = protoKindNames[pf.kind]
+			}
+			var label string
+			switch {
+			case pf.repeated:
+				label = "repeated "
+			case pf.optional:
+				label = "optional "
+			}
+			fmt.Fprintf(w, "  %s%s %s = %d;\n", label, typeName, f.encodedName("proto"), pf.num)
+		}
+		fmt.Fprint(w, "}\n")
+	}
+	return nil
+}
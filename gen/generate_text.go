@@ -0,0 +1,483 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+	"unicode"
+
+	. "github.com/garslo/gogen"
+)
+
+// This file contains the generators for formats which store field values as
+// strings, i.e. environment variables and URL query parameters.
+
+// genUnmarshalEnv generates the UnmarshalEnv method. Each field is read from the
+// environment variable named by its "env" tag, and parsed according to its type.
+func genUnmarshalEnv(mtyp *marshalerType) (Function, error) {
+	var (
+		m        = newMarshalMethod(mtyp, "env", true)
+		recv     = m.receiver()
+		lookup   = Name(m.scope.newIdent("lookup"))
+		intertyp = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		dec      = Name(m.scope.newIdent("dec"))
+		str      = Name(m.scope.newIdent("str"))
+		ok       = Name(m.scope.newIdent("ok"))
+		val      = Name(m.scope.newIdent("val"))
+		strings  = Name(m.scope.parent.packageName("strings"))
+	)
+	fn := Function{
+		Receiver:    recv,
+		Name:        "UnmarshalEnv",
+		ReturnTypes: Types{{TypeName: "error"}},
+		Parameters:  Types{{Name: lookup.Name, TypeName: "func(string) (string, bool)"}},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: dec.Name, TypeName: intertyp.Name},
+		},
+	}
+	for _, f := range mtyp.Fields {
+		if f.function != nil || isIgnored(f, "env") {
+			continue
+		}
+		// Lists are comma-separated. The empty string is the empty list.
+		key := f.encodedName("env")
+		list := textList{
+			values: CallFunction{Func: Dotted{Receiver: strings, Name: "Split"}, Params: []Expression{str, stringLit{","}}},
+			cond:   NotEqual{Lhs: str, Rhs: stringLit{""}},
+		}
+		for _, k := range inputKeys(f, key) {
+			decode, err := m.decodeTextField(Dotted{Receiver: dec, Name: k.field}, val, f, k.key, str, list)
+			if err != nil {
+				return fn, fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+			}
+			fn.Body = append(fn.Body, If{
+				Init: assignMulti{
+					Lhs:    []Expression{str, ok},
+					Rhs:    CallFunction{Func: lookup, Params: []Expression{stringLit{k.key}}},
+					Define: true,
+				},
+				Condition: ok,
+				Body:      decode,
+			})
+		}
+	}
+	fn.Body = append(fn.Body, m.unmarshalConversions(dec, Name(recv.Name), "env")...)
+	fn.Body = append(fn.Body, m.unmarshalReturn())
+	return fn, nil
+}
+
+// genDecodeValues generates the DecodeValues method, which reads fields from URL
+// query parameters. Slices are read from repeated parameters.
+func genDecodeValues(mtyp *marshalerType) (Function, error) {
+	var (
+		m        = newMarshalMethod(mtyp, "form", true)
+		recv     = m.receiver()
+		values   = Name(m.scope.newIdent("values"))
+		intertyp = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		dec      = Name(m.scope.newIdent("dec"))
+		strs     = Name(m.scope.newIdent("strs"))
+		val      = Name(m.scope.newIdent("val"))
+		url      = m.scope.parent.packageName("net/url")
+	)
+	fn := Function{
+		Receiver:    recv,
+		Name:        "DecodeValues",
+		ReturnTypes: Types{{TypeName: "error"}},
+		Parameters:  Types{{Name: values.Name, TypeName: url + ".Values"}},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: dec.Name, TypeName: intertyp.Name},
+		},
+	}
+	if mtyp.strict {
+		known := m.knownKeys(func(f *marshalerField) (string, bool) {
+			return f.encodedName("form"), !isIgnored(f, "form")
+		})
+		fn.Body = append(fn.Body, m.checkKeys(values, known, false))
+	}
+	for _, f := range mtyp.Fields {
+		if f.function != nil || isIgnored(f, "form") {
+			continue
+		}
+		key := f.encodedName("form")
+		for _, k := range inputKeys(f, key) {
+			decode, err := m.decodeTextField(Dotted{Receiver: dec, Name: k.field}, val, f, k.key, Index{Value: strs, Index: Int(0)}, textList{values: strs})
+			if err != nil {
+				return fn, fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+			}
+			fn.Body = append(fn.Body, If{
+				Init:      DeclareAndAssign{Lhs: strs, Rhs: Index{Value: values, Index: stringLit{k.key}}},
+				Condition: NotEqual{Lhs: lenCall(strs), Rhs: Int(0)},
+				Body:      decode,
+			})
+		}
+	}
+	fn.Body = append(fn.Body, m.unmarshalConversions(dec, Name(recv.Name), "form")...)
+	fn.Body = append(fn.Body, m.unmarshalReturn())
+	return fn, nil
+}
+
+// genEncodeValues generates the EncodeValues method, which sets URL query
+// parameters. Slices are stored as repeated parameters.
+func genEncodeValues(mtyp *marshalerType) (Function, error) {
+	var (
+		m        = newMarshalMethod(mtyp, "form", false)
+		recv     = m.receiver()
+		values   = Name(m.scope.newIdent("values"))
+		intertyp = m.intermediateType(m.scope.newIdent(m.mtyp.orig.Obj().Name()))
+		enc      = Name(m.scope.newIdent("enc"))
+		url      = m.scope.parent.packageName("net/url")
+	)
+	fn := Function{
+		Receiver:    recv,
+		Name:        "EncodeValues",
+		ReturnTypes: Types{{TypeName: "error"}},
+		Parameters:  Types{{Name: values.Name, TypeName: url + ".Values"}},
+		Body: []Statement{
+			declStmt{intertyp},
+			Declare{Name: enc.Name, TypeName: intertyp.Name},
+		},
+	}
+	fn.Body = append(fn.Body, m.marshalConversions(Name(recv.Name), enc, "form")...)
+	for _, f := range mtyp.Fields {
+		if isIgnored(f, "form") {
+			continue
+		}
+		var (
+			key    = f.encodedName("form")
+			access = Dotted{Receiver: enc, Name: f.name}
+			typ    = f.typ
+		)
+		if isNonEmptyInterface(f.origTyp) {
+			typ = f.origTyp
+		}
+		encode, err := m.encodeTextField(values, access, typ, key)
+		if err != nil {
+			return fn, fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+		}
+		opts := strings.Split(reflect.StructTag(f.tag).Get("form"), ",")[1:]
+		if cond := omitCondition(access, typ, opts, mtyp.scope.qualify); cond != nil {
+			encode = []Statement{If{Condition: cond, Body: encode}}
+		}
+		fn.Body = append(fn.Body, encode...)
+	}
+	fn.Body = append(fn.Body, Return{Values: []Expression{NIL}})
+	return fn, nil
+}
+
+// isIgnored reports whether the field is excluded from the format by its struct tag.
+func isIgnored(f *marshalerField, format string) bool {
+	name, _, _ := strings.Cut(reflect.StructTag(f.tag).Get(format), ",")
+	return name == "-"
+}
+
+// textList is the input of a slice field.
+type textList struct {
+	values Expression // the list of strings
+	cond   Expression // condition for reading values, may be nil
+}
+
+// inputKey is a key which is read from the input.
+type inputKey struct {
+	key   string // key in the input
+	field string // intermediate struct field which receives the value
+}
+
+// inputKeys returns the keys of field f. The value of the alias is stored in a
+// separate field of the intermediate struct.
+func inputKeys(f *marshalerField, key string) []inputKey {
+	keys := []inputKey{{key, f.name}}
+	if f.opts.alias != "" {
+		keys = append(keys, inputKey{f.opts.alias, f.opts.aliasField})
+	}
+	return keys
+}
+
+// decodeTextField creates the statements which parse the input of a field and
+// store it into dst, a field of the intermediate struct. Slices are parsed from
+// list, all other types are parsed from the string str.
+func (m *marshalMethod) decodeTextField(dst Expression, val Var, f *marshalerField, key string, str Expression, list textList) ([]Statement, error) {
+	var (
+		qf     = m.mtyp.scope.qualify
+		typ    = ensureNilCheckable(f.typ)
+		valTyp = typ
+		result Expression
+	)
+	if ptr, ok := typ.(*types.Pointer); ok {
+		valTyp = ptr.Elem()
+		result = AddressOf{Value: val}
+	} else {
+		result = val
+	}
+
+	s := []Statement{Declare{Name: val.Name, TypeName: types.TypeString(valTyp, qf)}}
+	if slice, ok := valTyp.Underlying().(*types.Slice); ok && !m.hasTextUnmarshaler(valTyp) {
+		var (
+			elem    = Name(m.scope.newIdent("elem"))
+			elemStr = Name(m.scope.newIdent("s"))
+		)
+		parse, err := m.parseText(elemStr, elem, slice.Elem(), key)
+		if err != nil {
+			return nil, err
+		}
+		loop := Range{Value: elemStr, RangeValue: list.values}
+		loop.Body = append([]Statement{Declare{Name: elem.Name, TypeName: types.TypeString(slice.Elem(), qf)}}, parse...)
+		loop.Body = append(loop.Body, Assign{
+			Lhs: val,
+			Rhs: CallFunction{Func: Name("append"), Params: []Expression{val, elem}},
+		})
+		s = append(s, Assign{Lhs: val, Rhs: CallFunction{Func: Name("make"), Params: []Expression{Name(types.TypeString(valTyp, qf)), Int(0)}}})
+		if list.cond != nil {
+			s = append(s, If{Condition: list.cond, Body: []Statement{loop}})
+		} else {
+			s = append(s, loop)
+		}
+	} else {
+		parse, err := m.parseText(str, val, valTyp, key)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, parse...)
+	}
+	return append(s, Assign{Lhs: dst, Rhs: result}), nil
+}
+
+// parseText creates the statements which parse the string str into the
+// variable dst of type typ.
+func (m *marshalMethod) parseText(str Expression, dst Var, typ types.Type, key string) ([]Statement, error) {
+	var (
+		qf      = m.mtyp.scope.qualify
+		strconv = Name(m.scope.parent.packageName("strconv"))
+		err     = Name("err")
+	)
+	// parse creates a call to a parse function returning (value, error).
+	parse := func(fn Expression, resultTyp types.Type, args ...Expression) []Statement {
+		result := Name(m.scope.newIdent("v"))
+		return []Statement{
+			assignMulti{Lhs: []Expression{result, err}, Rhs: CallFunction{Func: fn, Params: args}, Define: true},
+			If{Condition: NotEqual{Lhs: err, Rhs: NIL}, Body: []Statement{Return{Values: []Expression{m.textError(key, err)}}}},
+			Assign{Lhs: dst, Rhs: convertSimple(result, resultTyp, typ, qf)},
+		}
+	}
+
+	if m.hasTextUnmarshaler(typ) {
+		call := CallFunction{
+			Func:   Dotted{Receiver: dst, Name: "UnmarshalText"},
+			Params: []Expression{CallFunction{Func: Name("[]byte"), Params: []Expression{str}}},
+		}
+		return []Statement{If{
+			Init:      DeclareAndAssign{Lhs: err, Rhs: call},
+			Condition: NotEqual{Lhs: err, Rhs: NIL},
+			Body:      []Statement{Return{Values: []Expression{m.textError(key, err)}}},
+		}}, nil
+	}
+	if isDuration(typ) {
+		time := Name(m.scope.parent.packageName("time"))
+		return parse(Dotted{Receiver: time, Name: "ParseDuration"}, typ, str), nil
+	}
+	if basic, ok := typ.Underlying().(*types.Basic); ok {
+		bits := basicBits(basic, strconv)
+		switch info := basic.Info(); {
+		case info&types.IsString != 0:
+			return []Statement{Assign{Lhs: dst, Rhs: convertSimple(str, types.Typ[types.String], typ, qf)}}, nil
+		case info&types.IsBoolean != 0:
+			return parse(Dotted{Receiver: strconv, Name: "ParseBool"}, types.Typ[types.Bool], str), nil
+		case info&types.IsUnsigned != 0:
+			return parse(Dotted{Receiver: strconv, Name: "ParseUint"}, types.Typ[types.Uint64], str, Int(10), bits), nil
+		case info&types.IsInteger != 0:
+			return parse(Dotted{Receiver: strconv, Name: "ParseInt"}, types.Typ[types.Int64], str, Int(10), bits), nil
+		case info&types.IsFloat != 0:
+			return parse(Dotted{Receiver: strconv, Name: "ParseFloat"}, types.Typ[types.Float64], str, bits), nil
+		}
+	}
+	return nil, fmt.Errorf("type %s can't be decoded from a string", types.TypeString(typ, qf))
+}
+
+// encodeTextField creates the statements which store the value of a field into
+// url.Values.
+func (m *marshalMethod) encodeTextField(values Var, v Expression, typ types.Type, key string) ([]Statement, error) {
+	var (
+		keyLit = stringLit{key}
+		set    = func(str Expression) Statement {
+			return CallFunction{Func: Dotted{Receiver: values, Name: "Set"}, Params: []Expression{keyLit, str}}
+		}
+		add = func(str Expression) Statement {
+			return CallFunction{Func: Dotted{Receiver: values, Name: "Add"}, Params: []Expression{keyLit, str}}
+		}
+	)
+	// Nil pointers are not encoded.
+	if ptr, ok := typ.(*types.Pointer); ok {
+		var (
+			s   []Statement
+			err error
+		)
+		if m.hasTextMarshaler(typ) {
+			s, err = m.formatText(v, typ, set)
+		} else {
+			s, err = m.formatText(Star{Value: v}, ptr.Elem(), set)
+		}
+		return []Statement{If{Condition: NotEqual{Lhs: v, Rhs: NIL}, Body: s}}, err
+	}
+	if slice, ok := typ.Underlying().(*types.Slice); ok && !m.hasTextMarshaler(typ) {
+		elem := Name(m.scope.newIdent("elem"))
+		s, err := m.formatText(elem, slice.Elem(), add)
+		return []Statement{
+			CallFunction{Func: Dotted{Receiver: values, Name: "Del"}, Params: []Expression{keyLit}},
+			Range{Value: elem, RangeValue: v, Body: s},
+		}, err
+	}
+	return m.formatText(v, typ, set)
+}
+
+// formatText creates the statements which convert v to a string and pass it to
+// the store function.
+func (m *marshalMethod) formatText(v Expression, typ types.Type, store func(Expression) Statement) ([]Statement, error) {
+	var (
+		qf      = m.mtyp.scope.qualify
+		strconv = Name(m.scope.parent.packageName("strconv"))
+		format  = func(fn string, argTyp types.Type, args ...Expression) []Statement {
+			args = append([]Expression{convertSimple(v, typ, argTyp, qf)}, args...)
+			return []Statement{store(CallFunction{Func: Dotted{Receiver: strconv, Name: fn}, Params: args})}
+		}
+	)
+	if m.hasTextMarshaler(typ) {
+		var (
+			b   = Name(m.scope.newIdent("b"))
+			err = Name("err")
+		)
+		return []Statement{ifElseStmt{
+			If: If{
+				Init: assignMulti{
+					Lhs:    []Expression{b, err},
+					Rhs:    CallFunction{Func: Dotted{Receiver: v, Name: "MarshalText"}},
+					Define: true,
+				},
+				Condition: NotEqual{Lhs: err, Rhs: NIL},
+				Body:      []Statement{Return{Values: []Expression{err}}},
+			},
+			Else: []Statement{store(CallFunction{Func: Name("string"), Params: []Expression{b}})},
+		}}, nil
+	}
+	if isDuration(typ) {
+		return []Statement{store(CallFunction{Func: Dotted{Receiver: v, Name: "String"}})}, nil
+	}
+	if basic, ok := typ.Underlying().(*types.Basic); ok {
+		switch info := basic.Info(); {
+		case info&types.IsString != 0:
+			return []Statement{store(convertSimple(v, typ, types.Typ[types.String], qf))}, nil
+		case info&types.IsBoolean != 0:
+			return format("FormatBool", types.Typ[types.Bool]), nil
+		case info&types.IsUnsigned != 0:
+			return format("FormatUint", types.Typ[types.Uint64], Int(10)), nil
+		case info&types.IsInteger != 0:
+			return format("FormatInt", types.Typ[types.Int64], Int(10)), nil
+		case info&types.IsFloat != 0:
+			return format("FormatFloat", types.Typ[types.Float64], Name("'g'"), Int(-1), basicBits(basic, strconv)), nil
+		}
+	}
+	return nil, fmt.Errorf("type %s can't be encoded as a string", types.TypeString(typ, qf))
+}
+
+// textError creates the error returned for an invalid input string.
+func (m *marshalMethod) textError(key string, err Expression) Expression {
+	fmt := Name(m.scope.parent.packageName("fmt"))
+	return CallFunction{
+		Func:   Dotted{Receiver: fmt, Name: "Errorf"},
+		Params: []Expression{stringLit{"invalid value for " + strings.ReplaceAll(key, "%", "%%") + ": %w"}, err},
+	}
+}
+
+// basicBits returns the bit size argument for parsing a number of type typ.
+// The size of int and uint depends on the target platform, so strconv.IntSize
+// is used for them.
+func basicBits(typ *types.Basic, strconv Expression) Expression {
+	switch typ.Kind() {
+	case types.Int8, types.Uint8:
+		return Int(8)
+	case types.Int16, types.Uint16:
+		return Int(16)
+	case types.Int32, types.Uint32, types.Float32:
+		return Int(32)
+	case types.Int64, types.Uint64, types.Float64:
+		return Int(64)
+	default:
+		return Dotted{Receiver: strconv, Name: "IntSize"}
+	}
+}
+
+// hasTextUnmarshaler reports whether *typ implements encoding.TextUnmarshaler.
+func (m *marshalMethod) hasTextUnmarshaler(typ types.Type) bool {
+	return types.Implements(types.NewPointer(typ), unmarshalerInterface("UnmarshalText"))
+}
+
+// hasTextMarshaler reports whether typ implements encoding.TextMarshaler.
+func (m *marshalMethod) hasTextMarshaler(typ types.Type) bool {
+	return types.Implements(typ, marshalerInterface("MarshalText"))
+}
+
+// marshalerInterface returns an interface containing the method
+//
+//	name() ([]byte, error)
+//
+// which has the method set of encoding.TextMarshaler or json.Marshaler. The interface
+// is built directly instead of being imported, so the check can't fail when the
+// importer is unable to load the standard library.
+func marshalerInterface(name string) *types.Interface {
+	var (
+		output = types.NewVar(0, nil, "", byteSliceType)
+		err    = types.NewVar(0, nil, "", errorType)
+	)
+	return methodInterface(name, types.NewSignatureType(nil, nil, nil, nil, types.NewTuple(output, err), false))
+}
+
+// unmarshalerInterface returns an interface containing the method
+//
+//	name([]byte) error
+//
+// which has the method set of encoding.TextUnmarshaler or json.Unmarshaler.
+func unmarshalerInterface(name string) *types.Interface {
+	var (
+		input = types.NewVar(0, nil, "input", byteSliceType)
+		err   = types.NewVar(0, nil, "", errorType)
+	)
+	return methodInterface(name, types.NewSignatureType(nil, nil, nil, types.NewTuple(input), types.NewTuple(err), false))
+}
+
+func methodInterface(name string, sig *types.Signature) *types.Interface {
+	fn := types.NewFunc(0, nil, name, sig)
+	return types.NewInterfaceType([]*types.Func{fn}, nil).Complete()
+}
+
+func isDuration(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration"
+}
+
+// envName converts a Go identifier to upper snake case, e.g. HTTPPort becomes HTTP_PORT.
+func envName(name string) string {
+	var (
+		b     strings.Builder
+		runes = []rune(name)
+	)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
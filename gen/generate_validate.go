@@ -0,0 +1,102 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"go/types"
+	"slices"
+
+	. "github.com/garslo/gogen"
+)
+
+// genValidate generates the Validate method. It checks that required fields are
+// non-zero, that slices which are encoded as arrays have the right length, and
+// that fields satisfy their constraints. Constraints of optional fields are only
+// checked if the field is non-zero, like absent fields are not checked by the
+// unmarshaling methods.
+func genValidate(mtyp *marshalerType) Function {
+	m := newMarshalMethod(mtyp, "", false)
+	recv := m.receiver()
+	if mtyp.allErrors {
+		m.errs = Name(m.scope.newIdent("errs"))
+	}
+	fn := Function{
+		Receiver:    recv,
+		Name:        "Validate",
+		ReturnTypes: Types{{TypeName: "error"}},
+	}
+	if mtyp.allErrors {
+		fn.Body = append(fn.Body, Declare{Name: m.errs.Name, TypeName: "[]error"})
+	}
+	for _, f := range mtyp.Fields {
+		if f.function != nil {
+			continue
+		}
+		var (
+			v         = fieldAccess(Name(recv.Name), f)
+			fieldName = m.validateName(f)
+			checks    = m.checkConstraints(v, f, fieldName, nil)
+		)
+		if toArray := underlyingArray(f.typ); toArray != nil && underlyingSlice(f.origTyp) != nil {
+			cond := NotEqual{Lhs: lenCall(v), Rhs: Int(int(toArray.Len()))}
+			checks = append(m.checkField(cond, m.lengthError(fieldName, toArray.Len()), nil), checks...)
+		}
+		nonZero := nonZeroCheck(v, f.origTyp, mtyp.scope.qualify)
+		switch {
+		case nonZero != nil && m.requiredInAnyFormat(f):
+			checks = m.checkField(negate(nonZero), m.missingFieldError(fieldName), checks)
+		case nonZero != nil && len(checks) > 0:
+			checks = []Statement{If{Condition: nonZero, Body: checks}}
+		}
+		fn.Body = append(fn.Body, guardEmbedded(Name(recv.Name), f, checks)...)
+	}
+	fn.Body = append(fn.Body, m.unmarshalReturn())
+	return fn
+}
+
+// validateName returns the name of field f in the errors of Validate. Like the errors
+// of the unmarshaling methods, it is the encoded name in the first format of the type
+// which doesn't ignore the field. The Go name is used if all formats ignore it.
+func (m *marshalMethod) validateName(f *marshalerField) string {
+	for _, format := range m.mtyp.formats {
+		g, err := lookupFormat(format)
+		if err == nil && !isIgnored(f, g.TagKey()) {
+			return f.encodedName(g.TagKey())
+		}
+	}
+	return f.name
+}
+
+// requiredInAnyFormat reports whether the field is required by any format of the type.
+func (m *marshalMethod) requiredInAnyFormat(f *marshalerField) bool {
+	return slices.ContainsFunc(m.mtyp.formats, func(format string) bool {
+		g, err := lookupFormat(format)
+		return err == nil && f.isRequired(g.TagKey())
+	})
+}
+
+// nonZeroCheck creates the check for a non-zero value, or returns nil if the
+// value of the type can't be compared with its zero value.
+func nonZeroCheck(v Expression, typ types.Type, qf types.Qualifier) Expression {
+	_, isBasic := typ.Underlying().(*types.Basic)
+	if !isBasic && !isNilCheckable(typ) && !hasIsZero(typ) && !types.Comparable(typ) {
+		return nil
+	}
+	return notZero(v, typ, qf)
+}
+
+// negate creates the negation of a condition.
+func negate(cond Expression) Expression {
+	switch c := cond.(type) {
+	case NotEqual:
+		return Equals{Lhs: c.Lhs, Rhs: c.Rhs}
+	case Not:
+		return c.Value
+	case binaryExpr:
+		return Not{Value: parenExpr{c}}
+	default:
+		return Not{Value: c}
+	}
+}
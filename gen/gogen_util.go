@@ -0,0 +1,258 @@
+// Copyright 2017 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	. "github.com/garslo/gogen"
+)
+
+func errCheck(expr Expression) If {
+	err := Name("err")
+	return If{
+		Init:      DeclareAndAssign{Lhs: err, Rhs: expr},
+		Condition: NotEqual{Lhs: err, Rhs: NIL},
+		Body:      []Statement{Return{Values: []Expression{err}}},
+	}
+}
+
+// makeCall creates a call like `make(typ, len(lenfrom))`.
+func makeCall(typ types.Type, lenfrom Expression, qf types.Qualifier) Expression {
+	return CallFunction{Func: Name("make"), Params: []Expression{
+		Name(types.TypeString(typ, qf)),
+		CallFunction{Func: Name("len"), Params: []Expression{lenfrom}},
+	}}
+}
+
+// lenCall creates a call like `len(v)`.
+func lenCall(v Expression) Expression {
+	return CallFunction{Func: Name("len"), Params: []Expression{v}}
+}
+
+// errorsNewCall creates a call like `errors.New(errmsg)`.
+func errorsNewCall(sc *fileScope, errmsg string) Expression {
+	errors := sc.packageName("errors")
+	return CallFunction{
+		Func:   Dotted{Receiver: Name(errors), Name: "New"},
+		Params: []Expression{stringLit{errmsg}},
+	}
+}
+
+// hasSideEffects returns whether an expression may have side effects.
+func hasSideEffects(expr Expression) bool {
+	switch expr := expr.(type) {
+	case Var:
+		return false
+	case Dotted:
+		return hasSideEffects(expr.Receiver)
+	case Star:
+		return hasSideEffects(expr.Value)
+	case Index:
+		return hasSideEffects(expr.Index) && hasSideEffects(expr.Value)
+	default:
+		return true
+	}
+}
+
+// stringLit is a string literal expression. Strings containing double quotes
+// are written as raw string literals when possible.
+type stringLit struct {
+	V string
+}
+
+func (l stringLit) Expression() ast.Expr {
+	if strings.Contains(l.V, `"`) && strconv.CanBackquote(l.V) {
+		return &ast.BasicLit{Kind: token.STRING, Value: "`" + l.V + "`"}
+	}
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(l.V)}
+}
+
+// declStmt is a declaration statement.
+type declStmt struct {
+	d Declaration
+}
+
+func (ds declStmt) Statement() ast.Stmt {
+	return &ast.DeclStmt{Decl: ds.d.Declaration()}
+}
+
+// sliceExpr is a slicing expression Value[Low:High:Cap].
+type sliceExpr struct {
+	Value          Expression
+	Low, High, Cap Expression
+}
+
+func (s sliceExpr) Expression() ast.Expr {
+	sl := &ast.SliceExpr{X: s.Value.Expression()}
+	if s.Low != nil {
+		sl.Low = s.Low.Expression()
+	}
+	if s.High != nil {
+		sl.High = s.High.Expression()
+	}
+	if s.Cap != nil {
+		sl.Max = s.Cap.Expression()
+	}
+	return sl
+}
+
+// ifElseStmt is an if statement with an else branch.
+type ifElseStmt struct {
+	If
+	Else []Statement
+}
+
+func (s ifElseStmt) Statement() ast.Stmt {
+	stmt := s.If.Statement().(*ast.IfStmt)
+	if len(s.Else) > 0 {
+		block := &ast.BlockStmt{}
+		for _, st := range s.Else {
+			block.List = append(block.List, st.Statement())
+		}
+		stmt.Else = block
+	}
+	return stmt
+}
+
+// variadicCall is a call which passes its last argument as `args...`.
+type variadicCall struct {
+	Func   Expression
+	Params []Expression
+}
+
+func (c variadicCall) Expression() ast.Expr {
+	call := CallFunction{Func: c.Func, Params: c.Params}.Expression().(*ast.CallExpr)
+	call.Ellipsis = 1 // any valid position
+	return call
+}
+
+// compositeLit is a composite literal like `T{Key: Value}`.
+type compositeLit struct {
+	Type   Expression
+	Fields []keyValue
+}
+
+type keyValue struct {
+	Key   string
+	Value Expression
+}
+
+func (l compositeLit) Expression() ast.Expr {
+	lit := &ast.CompositeLit{Type: l.Type.Expression()}
+	for _, kv := range l.Fields {
+		lit.Elts = append(lit.Elts, &ast.KeyValueExpr{Key: ast.NewIdent(kv.Key), Value: kv.Value.Expression()})
+	}
+	return lit
+}
+
+// switchStmt is a switch statement. Tag may be nil.
+type switchStmt struct {
+	Init  Statement
+	Tag   Expression
+	Cases []caseClause
+}
+
+// caseClause is a case of a switch statement. A clause without
+// expressions is the default case.
+type caseClause struct {
+	List []Expression
+	Body []Statement
+}
+
+func (s switchStmt) Statement() ast.Stmt {
+	stmt := &ast.SwitchStmt{Body: &ast.BlockStmt{}}
+	if s.Init != nil {
+		stmt.Init = s.Init.Statement()
+	}
+	if s.Tag != nil {
+		stmt.Tag = s.Tag.Expression()
+	}
+	for _, c := range s.Cases {
+		clause := &ast.CaseClause{}
+		for _, e := range c.List {
+			clause.List = append(clause.List, e.Expression())
+		}
+		for _, st := range c.Body {
+			clause.Body = append(clause.Body, st.Statement())
+		}
+		stmt.Body.List = append(stmt.Body.List, clause)
+	}
+	return stmt
+}
+
+// typeAssert is a type assertion expression `Value.(Type)`.
+type typeAssert struct {
+	Value Expression
+	Type  string
+}
+
+func (e typeAssert) Expression() ast.Expr {
+	return &ast.TypeAssertExpr{X: e.Value.Expression(), Type: ast.NewIdent(e.Type)}
+}
+
+// assignMulti is an assignment with multiple variables on the left-hand side,
+// like `a, b = f()`. If Define is set, the statement is a short variable
+// declaration.
+type assignMulti struct {
+	Lhs    []Expression
+	Rhs    Expression
+	Define bool
+}
+
+func (a assignMulti) Statement() ast.Stmt {
+	stmt := &ast.AssignStmt{Tok: token.ASSIGN, Rhs: []ast.Expr{a.Rhs.Expression()}}
+	if a.Define {
+		stmt.Tok = token.DEFINE
+	}
+	for _, e := range a.Lhs {
+		stmt.Lhs = append(stmt.Lhs, e.Expression())
+	}
+	return stmt
+}
+
+// binaryExpr is a binary expression like `X && Y`.
+type binaryExpr struct {
+	Op   token.Token
+	X, Y Expression
+}
+
+func (e binaryExpr) Expression() ast.Expr {
+	return &ast.BinaryExpr{X: e.X.Expression(), Op: e.Op, Y: e.Y.Expression()}
+}
+
+// parenExpr is a parenthesized expression.
+type parenExpr struct {
+	X Expression
+}
+
+func (e parenExpr) Expression() ast.Expr {
+	return &ast.ParenExpr{X: e.X.Expression()}
+}
+
+// rangeKeys is a range statement over the keys of a map, `for Key := range X`.
+type rangeKeys struct {
+	Key, X Expression
+	Body   []Statement
+}
+
+func (r rangeKeys) Statement() ast.Stmt {
+	stmt := &ast.RangeStmt{Key: r.Key.Expression(), Tok: token.DEFINE, X: r.X.Expression(), Body: &ast.BlockStmt{}}
+	for _, st := range r.Body {
+		stmt.Body.List = append(stmt.Body.List, st.Statement())
+	}
+	return stmt
+}
+
+// continueStmt is a continue statement.
+type continueStmt struct{}
+
+func (continueStmt) Statement() ast.Stmt {
+	return &ast.BranchStmt{Tok: token.CONTINUE}
+}
@@ -0,0 +1,482 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/garslo/gogen"
+)
+
+// fieldOptions are the options given in the gencodec struct tag of a field.
+type fieldOptions struct {
+	required bool
+	def      string // default value as written in the tag
+	hasDef   bool
+
+	// defaultValue is the default converted to a Go expression. It is
+	// assigned on unmarshal when the field is absent.
+	defaultValue Expression
+
+	// constraints are checked after a field is decoded.
+	constraints []constraint
+
+	// alias is an old name of the field, which is accepted on unmarshal.
+	// aliasField is the name of the intermediate struct field which
+	// receives the value of the alias. It is set by loadOptions.
+	alias      string
+	aliasField string
+}
+
+// constraint is a validation option like min=1.
+type constraint struct {
+	name string // option name, e.g. "min"
+	text string // value as written in the tag
+
+	// set by loadOptions
+	values  []Expression // limit for min/max, allowed values for oneof
+	length  int          // for len
+	pattern string       // name of the regexp variable for pattern
+}
+
+// String returns the constraint as written in the tag.
+func (c constraint) String() string {
+	return c.name + "=" + c.text
+}
+
+// textOptions are the options whose values may contain commas.
+var textOptions = map[string]bool{"default": true, "pattern": true}
+
+// parseFieldOptions parses the value of a gencodec struct tag. Options are
+// separated by commas. The values of default and pattern may contain commas
+// because text which doesn't start a new option is appended to them.
+func parseFieldOptions(tag string) (opts fieldOptions, err error) {
+	if tag == "" {
+		return opts, nil
+	}
+	var text *string // value which the next item is appended to
+	for _, item := range strings.Split(tag, ",") {
+		key, value, hasValue := strings.Cut(item, "=")
+		switch {
+		case key == "required" && !hasValue:
+			opts.required = true
+			text = nil
+		case key == "default" && hasValue:
+			opts.def, opts.hasDef = value, true
+			text = &opts.def
+		case key == "alias" && hasValue:
+			if value == "" || value == "-" {
+				return opts, fmt.Errorf("invalid alias %q in gencodec tag", value)
+			}
+			if opts.alias != "" {
+				return opts, fmt.Errorf("duplicate option %q in gencodec tag", key)
+			}
+			opts.alias = value
+			text = nil
+		case isConstraint(key) && hasValue:
+			if opts.constraint(key) != nil {
+				return opts, fmt.Errorf("duplicate option %q in gencodec tag", key)
+			}
+			opts.constraints = append(opts.constraints, constraint{name: key, text: value})
+			text = nil
+			if textOptions[key] {
+				text = &opts.constraints[len(opts.constraints)-1].text
+			}
+		case text != nil:
+			*text += "," + item
+		default:
+			return opts, fmt.Errorf("invalid option %q in gencodec tag", item)
+		}
+	}
+	if opts.required && opts.hasDef {
+		return opts, errors.New("required field can't have a default value")
+	}
+	return opts, nil
+}
+
+func isConstraint(name string) bool {
+	switch name {
+	case "min", "max", "oneof", "pattern", "len":
+		return true
+	}
+	return false
+}
+
+// constraint returns the constraint with the given name, or nil if there is none.
+func (opts *fieldOptions) constraint(name string) *constraint {
+	for i := range opts.constraints {
+		if opts.constraints[i].name == name {
+			return &opts.constraints[i]
+		}
+	}
+	return nil
+}
+
+// loadOptions parses the gencodec struct tags of all fields.
+func (mtyp *marshalerType) loadOptions() error {
+	for _, f := range mtyp.Fields {
+		opts, err := parseFieldOptions(reflect.StructTag(f.tag).Get("gencodec"))
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+		}
+		if (opts.hasDef || len(opts.constraints) > 0 || opts.alias != "") && f.function != nil {
+			return fmt.Errorf("field %s.%s: options can't be used for a method", mtyp.name, f.name)
+		}
+		if opts.hasDef {
+			if opts.defaultValue, err = mtyp.defaultExpr(f.origTyp, opts.def); err != nil {
+				return fmt.Errorf("field %s.%s: invalid default value %q: %v", mtyp.name, f.name, opts.def, err)
+			}
+		}
+		for i := range opts.constraints {
+			c := &opts.constraints[i]
+			if err := mtyp.loadConstraint(f, c); err != nil {
+				return fmt.Errorf("field %s.%s: invalid option %s: %v", mtyp.name, f.name, c, err)
+			}
+		}
+		if err := checkMinMax(opts.constraint("min"), opts.constraint("max")); err != nil {
+			return fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+		}
+		if err := checkDefault(&opts); err != nil {
+			return fmt.Errorf("field %s.%s: %v", mtyp.name, f.name, err)
+		}
+		if opts.alias != "" {
+			opts.aliasField = mtyp.aliasFieldName(f)
+		}
+		f.opts = opts
+	}
+	return mtyp.checkAliases()
+}
+
+// checkAliases returns an error if an alias is equal to the encoded name of a
+// field or to another alias in any of the selected formats.
+func (mtyp *marshalerType) checkAliases() error {
+	for _, key := range mtyp.tagKeys() {
+		names := make(map[string]*marshalerField)
+		for _, f := range mtyp.Fields {
+			if !isIgnored(f, key) {
+				names[f.encodedName(key)] = f
+			}
+		}
+		aliases := make(map[string]*marshalerField)
+		for _, f := range mtyp.Fields {
+			if f.opts.alias == "" || isIgnored(f, key) {
+				continue
+			}
+			alias := f.opts.alias
+			if other := names[alias]; other == f {
+				return fmt.Errorf("field %s.%s: alias %q is the %s name of the field", mtyp.name, f.name, alias, key)
+			} else if other != nil {
+				return fmt.Errorf("field %s.%s: alias %q is the %s name of field %s", mtyp.name, f.name, alias, key, other.name)
+			}
+			if other := aliases[alias]; other != nil {
+				return fmt.Errorf("field %s.%s: alias %q is also used by field %s", mtyp.name, f.name, alias, other.name)
+			}
+			aliases[alias] = f
+		}
+	}
+	return nil
+}
+
+// aliasFieldName returns a name for the alias field of f in the intermediate
+// struct, which doesn't clash with other fields.
+func (mtyp *marshalerType) aliasFieldName(f *marshalerField) string {
+	taken := func(name string) bool {
+		for _, other := range mtyp.Fields {
+			if other.name == name || other.opts.aliasField == name {
+				return true
+			}
+		}
+		return false
+	}
+	name := f.name + "Alias"
+	for i := 2; taken(name); i++ {
+		name = fmt.Sprintf("%sAlias%d", f.name, i)
+	}
+	return name
+}
+
+// aliasTag returns the struct tag of the alias field of f. In the tag of each
+// format, the field name is replaced by the alias.
+func (mtyp *marshalerType) aliasTag(f *marshalerField) string {
+	var keys, tag []string
+	for _, format := range mtyp.formats {
+		if g, err := lookupFormat(format); err == nil && !slices.Contains(keys, g.TagKey()) {
+			keys = append(keys, g.TagKey())
+		}
+	}
+	for _, key := range keys {
+		name, opts, hasOpts := strings.Cut(reflect.StructTag(f.tag).Get(key), ",")
+		if name != "-" {
+			name = f.opts.alias
+		}
+		if hasOpts {
+			name += "," + opts
+		}
+		tag = append(tag, fmt.Sprintf("%s:%q", key, name))
+	}
+	return strings.Join(tag, " ")
+}
+
+// checkNoAlias returns an error if a field has an alias. It is used by formats
+// which don't identify fields by name.
+func checkNoAlias(mtyp *marshalerType, format string) error {
+	g, err := lookupFormat(format)
+	if err != nil {
+		return err
+	}
+	for _, f := range mtyp.Fields {
+		if f.opts.alias != "" && !isIgnored(f, g.TagKey()) {
+			return fmt.Errorf("field %s.%s: alias can't be used with format %s", mtyp.name, f.name, format)
+		}
+	}
+	return nil
+}
+
+// loadConstraint checks that the constraint can be applied to the field type and
+// converts its value.
+func (mtyp *marshalerType) loadConstraint(f *marshalerField, c *constraint) error {
+	var (
+		typ   = f.origTyp
+		basic = underlying[*types.Basic](typ)
+		info  types.BasicInfo
+	)
+	if basic != nil {
+		info = basic.Info()
+	}
+	switch c.name {
+	case "min", "max":
+		if info&(types.IsInteger|types.IsFloat) == 0 {
+			return errors.New(c.name + " can only be used with numeric types")
+		}
+		v, err := mtyp.constExpr(typ, c.text)
+		if err != nil {
+			return err
+		}
+		c.values = []Expression{v}
+	case "oneof":
+		if info&(types.IsInteger|types.IsString) == 0 {
+			return errors.New("oneof can only be used with string and integer types")
+		}
+		for _, text := range strings.Split(c.text, "|") {
+			var v Expression = constValue{stringLit{text}, constant.MakeString(text)}
+			if info&types.IsString == 0 {
+				var err error
+				if v, err = mtyp.constExpr(typ, text); err != nil {
+					return err
+				}
+			}
+			for _, other := range c.values {
+				if constant.Compare(constantOf(v), token.EQL, constantOf(other)) {
+					return fmt.Errorf("duplicate value %s", text)
+				}
+			}
+			c.values = append(c.values, v)
+		}
+	case "pattern":
+		if info&types.IsString == 0 {
+			return errors.New("pattern can only be used with string types")
+		}
+		if _, err := regexp.Compile(c.text); err != nil {
+			return err
+		}
+		c.pattern = mtyp.scope.newIdent(uncapitalize(mtyp.name) + f.name + "Pattern")
+		mtyp.scope.addImport("regexp")
+	case "len":
+		if info&types.IsString == 0 && underlyingSlice(typ) == nil && underlyingMap(typ) == nil {
+			return errors.New("len can only be used with string, slice and map types")
+		}
+		n, err := strconv.Atoi(c.text)
+		if err != nil || n < 0 {
+			return errors.New("length must be a non-negative integer")
+		}
+		c.length = n
+	}
+	return nil
+}
+
+// writePatterns declares the regular expressions of pattern constraints.
+func writePatterns(w io.Writer, mtyp *marshalerType) {
+	for _, f := range mtyp.Fields {
+		for _, c := range f.opts.constraints {
+			if c.pattern == "" {
+				continue
+			}
+			lit := strconv.Quote(c.text)
+			if strconv.CanBackquote(c.text) {
+				lit = "`" + c.text + "`"
+			}
+			fmt.Fprintf(w, "var %s = %s.MustCompile(%s)\n", c.pattern, mtyp.scope.packageName("regexp"), lit)
+		}
+	}
+}
+
+// checkMinMax verifies that min is not greater than max.
+func checkMinMax(min, max *constraint) error {
+	if min == nil || max == nil {
+		return nil
+	}
+	lo, hi := constantOf(min.values[0]), constantOf(max.values[0])
+	if lo != nil && hi != nil && constant.Compare(lo, token.GTR, hi) {
+		return fmt.Errorf("min %s is greater than max %s", min.text, max.text)
+	}
+	return nil
+}
+
+// checkDefault verifies that the default value satisfies the constraints of the field.
+func checkDefault(opts *fieldOptions) error {
+	v := constantOf(opts.defaultValue)
+	if v == nil {
+		return nil
+	}
+	for _, c := range opts.constraints {
+		var ok bool
+		switch c.name {
+		case "min", "max":
+			op := token.GEQ
+			if c.name == "max" {
+				op = token.LEQ
+			}
+			limit := constantOf(c.values[0])
+			ok = limit == nil || constant.Compare(v, op, limit)
+		case "oneof":
+			for _, x := range c.values {
+				ok = ok || constant.Compare(v, token.EQL, constantOf(x))
+			}
+		case "len":
+			ok = len(constant.StringVal(v)) == c.length
+		case "pattern":
+			ok = regexp.MustCompile(c.text).MatchString(constant.StringVal(v))
+		}
+		if !ok {
+			return fmt.Errorf("default value %q violates constraint %s", opts.def, c)
+		}
+	}
+	return nil
+}
+
+// defaultExpr creates the expression which is assigned to a field of type typ when
+// the field is absent in the input. For fields of string type, the default value is
+// the text itself.
+func (mtyp *marshalerType) defaultExpr(typ types.Type, text string) (Expression, error) {
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok || basic.Info()&(types.IsBoolean|types.IsNumeric|types.IsString) == 0 {
+		return nil, fmt.Errorf("type %s can't have a default value", types.TypeString(typ, mtyp.scope.qualify))
+	}
+	if basic.Info()&types.IsString != 0 {
+		return constValue{stringLit{text}, constant.MakeString(text)}, nil
+	}
+	return mtyp.constExpr(typ, text)
+}
+
+// constExpr converts the text of an option to a constant expression of type typ.
+// The text must be a constant expression assignable to typ, or a duration string
+// like "5s" if the type is time.Duration.
+func (mtyp *marshalerType) constExpr(typ types.Type, text string) (Expression, error) {
+	if isDuration(typ) {
+		if d, err := time.ParseDuration(text); err == nil {
+			expr := durationExpr(d, Name(mtyp.scope.packageName("time")))
+			return constValue{expr, constant.MakeInt64(int64(d))}, nil
+		}
+	}
+	basic := typ.Underlying().(*types.Basic)
+
+	// Check that the value is a constant which can be represented by the
+	// field type. The conversion to the underlying type catches overflows.
+	expr, err := parser.ParseExpr(text)
+	if err != nil {
+		return nil, errors.New("not an expression")
+	}
+	tv, err := mtyp.evalConst(text)
+	if err != nil {
+		return nil, err
+	}
+	if !types.AssignableTo(tv.Type, typ) {
+		return nil, fmt.Errorf("cannot use %s as %s", tv.Type, types.TypeString(typ, mtyp.scope.qualify))
+	}
+	if _, err := mtyp.evalConst(basic.Name() + "(" + text + ")"); err != nil {
+		return nil, err
+	}
+	mtyp.scope.addExprReferences(expr)
+	return constValue{astExpr{clearPositions(expr)}, tv.Value}, nil
+}
+
+// evalConst evaluates a constant expression in the scope of the package.
+func (mtyp *marshalerType) evalConst(expr string) (types.TypeAndValue, error) {
+	tv, err := types.Eval(mtyp.fs, mtyp.scope.pkg, token.NoPos, expr)
+	if err != nil {
+		var terr types.Error
+		if errors.As(err, &terr) {
+			err = errors.New(terr.Msg) // remove the meaningless position
+		}
+		return tv, err
+	}
+	if tv.Value == nil {
+		return tv, errors.New("not a constant")
+	}
+	return tv, nil
+}
+
+// constValue is an expression with a known constant value.
+type constValue struct {
+	expr  Expression
+	value constant.Value
+}
+
+func (c constValue) Expression() ast.Expr {
+	return c.expr.Expression()
+}
+
+// constantOf returns the value of a constant expression, or nil if it is unknown.
+func constantOf(e Expression) constant.Value {
+	if c, ok := e.(constValue); ok {
+		return c.value
+	}
+	return nil
+}
+
+// durationExpr creates a readable expression for the duration d, e.g. 5 * time.Second.
+func durationExpr(d time.Duration, timePkg Expression) Expression {
+	units := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"Hour", time.Hour},
+		{"Minute", time.Minute},
+		{"Second", time.Second},
+		{"Millisecond", time.Millisecond},
+		{"Microsecond", time.Microsecond},
+	}
+	if d != 0 {
+		for _, u := range units {
+			if d%u.d == 0 {
+				n := Name(strconv.FormatInt(int64(d/u.d), 10))
+				return binaryExpr{Op: token.MUL, X: n, Y: Dotted{Receiver: timePkg, Name: u.name}}
+			}
+		}
+	}
+	return Name(strconv.FormatInt(int64(d), 10))
+}
+
+// defaultFor returns the default value of the field when decoding the given format,
+// or nil if the field has no default.
+func (mf *marshalerField) defaultFor(format string) Expression {
+	if mf.isSkipped(format) {
+		return nil
+	}
+	return mf.opts.defaultValue
+}
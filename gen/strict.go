@@ -0,0 +1,289 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	. "github.com/garslo/gogen"
+)
+
+// In strict mode, the unmarshaling methods reject keys of the input which don't
+// belong to any field. Most formats decode the input a second time into a map, and
+// the keys of the map are checked against the known keys. The xml format and formats
+// added with RegisterFormat don't support strict mode. Keys of fields which
+// are generated from methods are known, so the output of the marshaling methods
+// is always accepted.
+
+// strictFormat describes how keys are matched by a template format.
+type strictFormat struct {
+	keysType string // type of the map which receives the keys
+	fold     bool   // keys are matched case-insensitively
+	lower    bool   // keys also match if the input key converted to lower case matches
+
+	// key returns the key of a field, or false if the field is never decoded.
+	key func(f *marshalerField) (string, bool)
+
+	// decode creates the statements which decode the input into dec and keys. It
+	// can also return a condition for matching keys case-insensitively.
+	decode func(m *marshalMethod, names map[string]string, dec, keys Expression, call Expression) ([]Statement, Expression)
+}
+
+// strictFormats contains the template formats which support strict mode.
+var strictFormats = map[string]strictFormat{
+	"json": {
+		keysType: "map[string]$json.RawMessage",
+		fold:     true,
+		key:      jsonStrictKey,
+		decode:   decodeBytesTwice("json"),
+	},
+	"cbor": {
+		keysType: "map[string]$cbor.RawMessage",
+		fold:     true,
+		key:      encodedStrictKey("cbor"),
+		decode:   decodeBytesTwice("cbor"),
+	},
+	"bson": {
+		keysType: "map[string]$bson.RawValue",
+		lower:    true,
+		key:      encodedStrictKey("bson"),
+		decode:   decodeBytesTwice("bson"),
+	},
+	"msgpack": {
+		keysType: "map[string]$msgpack.RawMessage",
+		key:      encodedStrictKey("msgpack"),
+		decode: func(m *marshalMethod, names map[string]string, dec, keys Expression, call Expression) ([]Statement, Expression) {
+			// The decoder can only be read once, so the value is read into a
+			// buffer first. Options of the decoder don't apply to the buffer.
+			var (
+				msgpack = Name(names["msgpack"])
+				input   = Name(m.scope.newIdent("input"))
+			)
+			return []Statement{
+				Declare{Name: input.Name, TypeName: names["msgpack"] + ".RawMessage"},
+				errCheck(CallFunction{Func: Dotted{Receiver: Name(names["decoder"]), Name: "Decode"}, Params: []Expression{AddressOf{Value: input}}}),
+				errCheck(CallFunction{Func: Dotted{Receiver: msgpack, Name: "Unmarshal"}, Params: []Expression{input, dec}}),
+				errCheck(CallFunction{Func: Dotted{Receiver: msgpack, Name: "Unmarshal"}, Params: []Expression{input, keys}}),
+			}, nil
+		},
+	},
+	"json2": {
+		keysType: "map[string]$jsontext.Value",
+		key:      jsonStrictKey,
+		decode: func(m *marshalMethod, names map[string]string, dec, keys Expression, call Expression) ([]Statement, Expression) {
+			// The decoder can only be read once, so the object is read into a
+			// buffer first. Options of the decoder apply to the buffer, and keys
+			// are matched case-insensitively if the options say so. This is the
+			// case when the method is called by encoding/json.
+			var (
+				json    = Name(names["json"])
+				input   = Name(m.scope.newIdent("input"))
+				fold    = Name(m.scope.newIdent("fold"))
+				options = CallFunction{Func: Dotted{Receiver: Name(names["decoder"]), Name: "Options"}}
+			)
+			return []Statement{
+				Declare{Name: input.Name, TypeName: names["jsontext"] + ".Value"},
+				errCheck(CallFunction{
+					Func:   Dotted{Receiver: json, Name: "UnmarshalDecode"},
+					Params: []Expression{Name(names["decoder"]), AddressOf{Value: input}},
+				}),
+				errCheck(CallFunction{Func: Dotted{Receiver: json, Name: "Unmarshal"}, Params: []Expression{input, dec, options}}),
+				errCheck(CallFunction{Func: Dotted{Receiver: json, Name: "Unmarshal"}, Params: []Expression{input, keys, options}}),
+				assignMulti{
+					Lhs: []Expression{fold, Name("_")},
+					Rhs: CallFunction{
+						Func:   Dotted{Receiver: json, Name: "GetOption"},
+						Params: []Expression{options, Dotted{Receiver: json, Name: "MatchCaseInsensitiveNames"}},
+					},
+					Define: true,
+				},
+			}, fold
+		},
+	},
+	"yaml": {
+		keysType: "map[string]interface{}",
+		key:      tagStrictKey("yaml", strings.ToLower),
+		decode:   decodeTwice("unmarshal"),
+	},
+	"toml": {
+		keysType: "map[string]interface{}",
+		fold:     true,
+		key:      tagStrictKey("toml", func(name string) string { return name }),
+		decode:   decodeTwice("unmarshal"),
+	},
+}
+
+// decodeBytesTwice creates the decoding statements for formats which unmarshal a
+// byte slice using the Unmarshal function of package pkg, like encoding/json.
+func decodeBytesTwice(pkg string) func(*marshalMethod, map[string]string, Expression, Expression, Expression) ([]Statement, Expression) {
+	return func(m *marshalMethod, names map[string]string, dec, keys Expression, call Expression) ([]Statement, Expression) {
+		unmarshal := Dotted{Receiver: Name(names[pkg]), Name: "Unmarshal"}
+		return []Statement{
+			errCheck(call),
+			errCheck(CallFunction{Func: unmarshal, Params: []Expression{Name(names["input"]), keys}}),
+		}, nil
+	}
+}
+
+// decodeTwice creates the decoding statements for formats which call a function
+// to unmarshal, like UnmarshalYAML.
+func decodeTwice(fn string) func(*marshalMethod, map[string]string, Expression, Expression, Expression) ([]Statement, Expression) {
+	return func(m *marshalMethod, names map[string]string, dec, keys Expression, call Expression) ([]Statement, Expression) {
+		return []Statement{
+			errCheck(call),
+			errCheck(CallFunction{Func: Name(names[fn]), Params: []Expression{keys}}),
+		}, nil
+	}
+}
+
+// strictDecode creates the decoding statements of a template format in strict mode.
+func (m *marshalMethod) strictDecode(format string, names map[string]string, dec Var, call Expression, expand func(string) string) ([]Statement, error) {
+	if format == "rlp" {
+		// Package rlp rejects lists with too many elements.
+		return []Statement{errCheck(call)}, nil
+	}
+	sf, ok := strictFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("format %s doesn't support strict mode", format)
+	}
+	keys := Name(m.scope.newIdent("keys"))
+	decode, foldIf := sf.decode(m, names, AddressOf{Value: dec}, AddressOf{Value: keys}, call)
+	s := append([]Statement{Declare{Name: keys.Name, TypeName: expand(sf.keysType)}}, decode...)
+	known := m.knownKeys(sf.key)
+	if sf.lower && len(known) > 0 {
+		// Keys without exact match are converted to lower case and compared again.
+		var (
+			key     = Name(m.scope.newIdent("key"))
+			strings = Name(m.scope.parent.packageName("strings"))
+			fail    = Return{Values: []Expression{m.unknownKeyError(key)}}
+			lower   = m.matchKey(CallFunction{Func: Dotted{Receiver: strings, Name: "ToLower"}, Params: []Expression{key}}, known, false, []Statement{fail})
+			exact   = m.matchKey(key, known, false, []Statement{lower})
+		)
+		return append(s, rangeKeys{Key: key, X: keys, Body: []Statement{exact}}), nil
+	}
+	if foldIf == nil || len(known) == 0 {
+		return append(s, m.checkKeys(keys, known, sf.fold)), nil
+	}
+	// Keys without exact match are compared case-insensitively if foldIf holds.
+	var (
+		key    = Name(m.scope.newIdent("key"))
+		fail   = Return{Values: []Expression{m.unknownKeyError(key)}}
+		folded = m.matchKey(key, known, true, []Statement{fail})
+		exact  = m.matchKey(key, known, false, []Statement{If{Condition: Not{Value: foldIf}, Body: []Statement{fail}}, folded})
+	)
+	return append(s, rangeKeys{Key: key, X: keys, Body: []Statement{exact}}), nil
+}
+
+// knownKeys returns the keys of all fields, including aliases.
+func (m *marshalMethod) knownKeys(key func(*marshalerField) (string, bool)) []string {
+	var keys []string
+	for _, f := range m.mtyp.Fields {
+		if k, ok := key(f); ok {
+			keys = append(keys, k)
+			if f.opts.alias != "" {
+				keys = append(keys, f.opts.alias)
+			}
+		}
+	}
+	return keys
+}
+
+// checkKeys creates a loop which returns an error for unknown keys of a map.
+func (m *marshalMethod) checkKeys(keys Expression, known []string, fold bool) Statement {
+	key := Name(m.scope.newIdent("key"))
+	fail := Return{Values: []Expression{m.unknownKeyError(key)}}
+	if len(known) == 0 {
+		return rangeKeys{Key: key, X: keys, Body: []Statement{fail}}
+	}
+	return rangeKeys{Key: key, X: keys, Body: []Statement{m.matchKey(key, known, fold, []Statement{fail})}}
+}
+
+// matchKey creates a switch which runs unknown if key is not known.
+func (m *marshalMethod) matchKey(key Expression, known []string, fold bool, unknown []Statement) Statement {
+	var (
+		tag  = key
+		list []Expression
+	)
+	if fold {
+		strings := Name(m.scope.parent.packageName("strings"))
+		tag = CallFunction{Func: Dotted{Receiver: strings, Name: "ToLower"}, Params: []Expression{key}}
+	}
+	var seen []string
+	for _, k := range known {
+		if fold {
+			k = strings.ToLower(k)
+		}
+		if !slices.Contains(seen, k) {
+			seen = append(seen, k)
+			list = append(list, stringLit{k})
+		}
+	}
+	return switchStmt{Tag: tag, Cases: []caseClause{{List: list}, {Body: unknown}}}
+}
+
+// unknownKeyError creates the error value for an unknown key.
+func (m *marshalMethod) unknownKeyError(key Expression) Expression {
+	return m.unknownError("'%s'", key, key)
+}
+
+// unknownFieldNumberError creates the error value for an unknown protobuf field number.
+func (m *marshalMethod) unknownFieldNumberError(num Expression) Expression {
+	fmt := Name(m.scope.parent.packageName("fmt"))
+	return m.unknownError("%d", num, CallFunction{Func: Dotted{Receiver: fmt, Name: "Sprint"}, Params: []Expression{num}})
+}
+
+// unknownError creates the error value for an unknown field. The key is formatted
+// into the message using verb. For typed errors, field is the key as a string.
+func (m *marshalMethod) unknownError(verb string, key, field Expression) Expression {
+	if m.mtyp.typedErrors {
+		return m.fieldErrorLit(field, "ReasonUnknown", nil)
+	}
+	var (
+		fmt    = Name(m.scope.parent.packageName("fmt"))
+		msg    = "unknown field " + verb + " for " + m.mtyp.name
+		params []Expression
+	)
+	if m.errPos != nil {
+		msg = "line %d, column %d: " + msg
+		params = append(params, Dotted{Receiver: m.errPos, Name: "Line"}, Dotted{Receiver: m.errPos, Name: "Column"})
+	}
+	params = append([]Expression{stringLit{msg}}, append(params, key)...)
+	return CallFunction{Func: Dotted{Receiver: fmt, Name: "Errorf"}, Params: params}
+}
+
+// jsonStrictKey returns the JSON object key of a field.
+func jsonStrictKey(f *marshalerField) (string, bool) {
+	key, _, ok := f.jsonKey()
+	return key, ok
+}
+
+// encodedStrictKey returns a function which computes the key of a field like
+// encodedName does for the format.
+func encodedStrictKey(format string) func(*marshalerField) (string, bool) {
+	return func(f *marshalerField) (string, bool) {
+		if tagName(f.tag, format) == "-" {
+			return "", false
+		}
+		return f.encodedName(format), true
+	}
+}
+
+// tagStrictKey returns a function which computes the key of a field from the
+// struct tag of the format. If the tag has no name, the key is derived from the
+// field name.
+func tagStrictKey(format string, fieldKey func(string) string) func(*marshalerField) (string, bool) {
+	return func(f *marshalerField) (string, bool) {
+		if isIgnored(f, format) {
+			return "", false
+		}
+		if name, _, _ := strings.Cut(reflect.StructTag(f.tag).Get(format), ","); name != "" {
+			return name, true
+		}
+		return fieldKey(f.name), true
+	}
+}
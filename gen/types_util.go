@@ -2,15 +2,17 @@
 // Use of this source code is governed by the MIT license,
 // which can be found in the LICENSE file.
 
-package main
+package gen
 
 import (
 	"errors"
 	"fmt"
+	"go/ast"
 	"go/types"
 	"io"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 // walkNamedTypes runs the callback for all named types contained in the given type.
@@ -24,6 +26,11 @@ func walkNamedTypes(typ types.Type, callback func(typeName *types.TypeName)) {
 		walkNamedTypes(typ.Elem(), callback)
 	case *types.Named:
 		callback(typ.Obj())
+		for i := 0; i < typ.TypeArgs().Len(); i++ {
+			walkNamedTypes(typ.TypeArgs().At(i), callback)
+		}
+	case *types.TypeParam:
+		callback(typ.Obj())
 	case *types.Pointer:
 		walkNamedTypes(typ.Elem(), callback)
 	case *types.Slice:
@@ -67,7 +74,47 @@ func lookupType(scope *types.Scope, name string) (*types.Named, error) {
 	if !ok {
 		return nil, errors.New("not a type")
 	}
-	return typ.Type().(*types.Named), nil
+	named, ok := types.Unalias(typ.Type()).(*types.Named)
+	if !ok {
+		return nil, errors.New("not a named type")
+	}
+	if named.TypeArgs().Len() > 0 {
+		return nil, errors.New("can't use instantiated generic type")
+	}
+	return named, nil
+}
+
+// typeParamNames returns the type parameter list of a generic type as used in a
+// method receiver, e.g. "[K, V]". It returns the empty string for non-generic types.
+func typeParamNames(tparams *types.TypeParamList) string {
+	if tparams.Len() == 0 {
+		return ""
+	}
+	names := make([]string, tparams.Len())
+	for i := range names {
+		names[i] = tparams.At(i).Obj().Name()
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// typeParamDecl returns the type parameter list of a generic type including
+// constraints, e.g. "[K comparable, V any]".
+func typeParamDecl(tparams *types.TypeParamList, qf types.Qualifier) string {
+	decls := make([]string, tparams.Len())
+	for i := range decls {
+		tp := tparams.At(i)
+		decls[i] = tp.Obj().Name() + " " + types.TypeString(tp.Constraint(), qf)
+	}
+	return "[" + strings.Join(decls, ", ") + "]"
+}
+
+// typeArgs returns the type parameters of a generic type as a list of types.
+func typeArgs(tparams *types.TypeParamList) []types.Type {
+	args := make([]types.Type, tparams.Len())
+	for i := range args {
+		args[i] = tparams.At(i)
+	}
+	return args
 }
 
 func isPointer(typ types.Type) bool {
@@ -75,11 +122,21 @@ func isPointer(typ types.Type) bool {
 	return ok
 }
 
+func isString(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
 func isNonEmptyInterface(typ types.Type) bool {
 	iftype := underlying[*types.Interface](typ)
 	return iftype != nil && iftype.NumMethods() > 0
 }
 
+func isUnnamedInterface(typ types.Type) bool {
+	_, ok := typ.(*types.Interface)
+	return ok
+}
+
 func isInterface(typ types.Type) bool {
 	return underlying[*types.Interface](typ) != nil
 }
@@ -222,6 +279,13 @@ func (s *fileScope) addImport(path string) {
 	s.rebuildImports()
 }
 
+// addPackage adds an import of the given package without loading it.
+// This is used for packages that the importer cannot load.
+func (s *fileScope) addPackage(path, name string) {
+	s.insertImport(types.NewPackage(path, name))
+	s.rebuildImports()
+}
+
 // addReferences marks all names referenced by typ as used.
 func (s *fileScope) addReferences(typ types.Type) {
 	walkNamedTypes(typ, func(typeName *types.TypeName) {
@@ -235,6 +299,17 @@ func (s *fileScope) addReferences(typ types.Type) {
 	s.rebuildImports()
 }
 
+// addExprReferences marks the package-level names used by expr as used, so
+// variables of the generated code don't shadow them.
+func (s *fileScope) addExprReferences(expr ast.Expr) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && s.pkg.Scope().Lookup(id.Name) != nil {
+			s.otherNames[id.Name] = true
+		}
+		return true
+	})
+}
+
 // insertImport adds pkg to the list of known imports.
 // This method should not be used directly because it doesn't
 // rebuild the import name cache.
@@ -242,7 +317,7 @@ func (s *fileScope) insertImport(pkg *types.Package) {
 	i := sort.Search(len(s.imports), func(i int) bool {
 		return s.imports[i].Path() >= pkg.Path()
 	})
-	if i < len(s.imports) && s.imports[i] == pkg {
+	if i < len(s.imports) && s.imports[i].Path() == pkg.Path() {
 		return
 	}
 	s.imports = append(s.imports[:i], append([]*types.Package{pkg}, s.imports[i:]...)...)
@@ -269,6 +344,17 @@ func (s *fileScope) maybeRenameImport(pkg *types.Package) {
 	s.importsByName[name] = pkg
 }
 
+// newIdent creates a new identifier at file scope that doesn't clash with
+// imports or other identifiers.
+func (s *fileScope) newIdent(base string) string {
+	name := base
+	for i := 0; s.isNameTaken(name); i++ {
+		name = base + strconv.Itoa(i)
+	}
+	s.otherNames[name] = true
+	return name
+}
+
 // isNameTaken reports whether the given name is used by an import or other identifier.
 func (s *fileScope) isNameTaken(name string) bool {
 	return s.importsByName[name] != nil || s.otherNames[name] || types.Universe.Lookup(name) != nil
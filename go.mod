@@ -6,7 +6,7 @@ toolchain go1.23.4
 
 require (
 	github.com/garslo/gogen v0.0.0-20170306192744-1d203ffc1f61
-	github.com/kylelemons/godebug v0.0.0-20170224010052-a616ab194758
+	github.com/kylelemons/godebug v1.1.0
 	golang.org/x/tools v0.29.0
 )
 
@@ -0,0 +1,22 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -field-override Xo -formats json,yaml -all-errors -out output.go
+
+package allerrors
+
+type replacedInt int
+
+type X struct {
+	A     int    `gencodec:"required"`
+	B     string `gencodec:"required"`
+	Arr   [2]int
+	ArrRq [2]int `gencodec:"required"`
+	Opt   int
+}
+
+type Xo struct {
+	Arr   []replacedInt
+	ArrRq []int
+}
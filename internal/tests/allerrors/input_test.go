@@ -0,0 +1,29 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package allerrors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAllErrorsJSON(t *testing.T) {
+	var x X
+	err := json.Unmarshal([]byte(`{"arr": [1], "arrRq": [1, 2, 3]}`), &x)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	want := "missing required field 'a' for X\n" +
+		"missing required field 'b' for X\n" +
+		"field 'arr' has wrong length, need 2 items\n" +
+		"field 'arrRq' has wrong length, need 2 items"
+	if err.Error() != want {
+		t.Fatalf("wrong error:\n%s\nwant:\n%s", err, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a": 1, "b": "x", "arrRq": [1, 2]}`), &x); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+}
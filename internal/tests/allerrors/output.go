@@ -0,0 +1,148 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package allerrors
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	type X struct {
+		A     int    `gencodec:"required"`
+		B     string `gencodec:"required"`
+		Arr   []replacedInt
+		ArrRq []int `gencodec:"required"`
+		Opt   int
+	}
+	var enc X
+	enc.A = x.A
+	enc.B = x.B
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	enc.ArrRq = x.ArrRq[:]
+	enc.Opt = x.Opt
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input []byte) error {
+	type X struct {
+		A     *int    `gencodec:"required"`
+		B     *string `gencodec:"required"`
+		Arr   []replacedInt
+		ArrRq []int `gencodec:"required"`
+		Opt   *int
+	}
+	var dec X
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	var errs []error
+	if dec.A == nil {
+		errs = append(errs, errors.New("missing required field 'a' for X"))
+	} else {
+		x.A = *dec.A
+	}
+	if dec.B == nil {
+		errs = append(errs, errors.New("missing required field 'b' for X"))
+	} else {
+		x.B = *dec.B
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			errs = append(errs, errors.New("field 'arr' has wrong length, need 2 items"))
+		} else {
+			for k, v := range dec.Arr {
+				x.Arr[k] = int(v)
+			}
+		}
+	}
+	if dec.ArrRq == nil {
+		errs = append(errs, errors.New("missing required field 'arrRq' for X"))
+	} else {
+		if len(dec.ArrRq) != len(x.ArrRq) {
+			errs = append(errs, errors.New("field 'arrRq' has wrong length, need 2 items"))
+		} else {
+			copy(x.ArrRq[:], dec.ArrRq)
+		}
+	}
+	if dec.Opt != nil {
+		x.Opt = *dec.Opt
+	}
+	return errors.Join(errs...)
+}
+
+// MarshalYAML marshals as YAML.
+func (x X) MarshalYAML() (interface{}, error) {
+	type X struct {
+		A     int    `gencodec:"required"`
+		B     string `gencodec:"required"`
+		Arr   []replacedInt
+		ArrRq []int `gencodec:"required"`
+		Opt   int
+	}
+	var enc X
+	enc.A = x.A
+	enc.B = x.B
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	enc.ArrRq = x.ArrRq[:]
+	enc.Opt = x.Opt
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (x *X) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type X struct {
+		A     *int    `gencodec:"required"`
+		B     *string `gencodec:"required"`
+		Arr   []replacedInt
+		ArrRq []int `gencodec:"required"`
+		Opt   *int
+	}
+	var dec X
+	if err := unmarshal(&dec); err != nil {
+		return err
+	}
+	var errs []error
+	if dec.A == nil {
+		errs = append(errs, errors.New("missing required field 'a' for X"))
+	} else {
+		x.A = *dec.A
+	}
+	if dec.B == nil {
+		errs = append(errs, errors.New("missing required field 'b' for X"))
+	} else {
+		x.B = *dec.B
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			errs = append(errs, errors.New("field 'arr' has wrong length, need 2 items"))
+		} else {
+			for k, v := range dec.Arr {
+				x.Arr[k] = int(v)
+			}
+		}
+	}
+	if dec.ArrRq == nil {
+		errs = append(errs, errors.New("missing required field 'arrRq' for X"))
+	} else {
+		if len(dec.ArrRq) != len(x.ArrRq) {
+			errs = append(errs, errors.New("field 'arrRq' has wrong length, need 2 items"))
+		} else {
+			copy(x.ArrRq[:], dec.ArrRq)
+		}
+	}
+	if dec.Opt != nil {
+		x.Opt = *dec.Opt
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -field-override Xo -formats bson -out output.go
+
+package bson
+
+type replacedInt int64
+
+type X struct {
+	ID    string `bson:"_id" gencodec:"required"`
+	Name  string `bson:"name,omitempty"`
+	Arr   [2]int `bson:"arr"`
+	Slice []int  `bson:"slice"`
+	Skip  int    `bson:"-"`
+	// Package bson uses the lowercase Go name as the key, and ignores json tags.
+	PlainValue int `json:"plain_value"`
+}
+
+type Xo struct {
+	Arr   []replacedInt
+	Slice []replacedInt
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package bson
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestBSONKeys(t *testing.T) {
+	x := X{ID: "a", Arr: [2]int{2, 3}, Slice: []int{4}, Skip: 5, PlainValue: 6}
+	enc, err := bson.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d bson.D
+	if err := bson.Unmarshal(enc, &d); err != nil {
+		t.Fatal(err)
+	}
+	// ID is stored as the document _id. Name is omitted because it is empty, Skip
+	// is ignored and PlainValue uses the lowercase Go name.
+	var keys []string
+	for _, e := range d {
+		keys = append(keys, e.Key)
+	}
+	want := []string{"_id", "arr", "slice", "plainvalue"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("wrong keys %q, want %q", keys, want)
+	}
+
+	var dec X
+	if err := bson.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	x.Skip = 0
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+func TestBSONOmitEmpty(t *testing.T) {
+	enc, err := bson.Marshal(X{ID: "a", Name: "n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m bson.M
+	if err := bson.Unmarshal(enc, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["name"] != "n" {
+		t.Fatalf("non-empty name not encoded: %v", m)
+	}
+}
+
+func TestBSONErrors(t *testing.T) {
+	tests := []struct {
+		input bson.M
+		err   string
+	}{
+		{bson.M{"name": "x", "id": "a"}, "missing required field '_id' for X"},
+		{bson.M{"_id": "a", "arr": bson.A{1}}, "field 'arr' has wrong length, need 2 items"},
+	}
+	for _, test := range tests {
+		enc, _ := bson.Marshal(test.input)
+		var x X
+		err := bson.Unmarshal(enc, &x)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("input %v: wrong error %v\n want %s", test.input, err, test.err)
+		}
+	}
+}
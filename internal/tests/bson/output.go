@@ -0,0 +1,83 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package bson
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalBSON marshals as BSON.
+func (x X) MarshalBSON() ([]byte, error) {
+	type X struct {
+		ID         string        `bson:"_id" gencodec:"required"`
+		Name       string        `bson:"name,omitempty"`
+		Arr        []replacedInt `bson:"arr"`
+		Slice      []replacedInt `bson:"slice"`
+		Skip       int           `bson:"-"`
+		PlainValue int           `json:"plain_value"`
+	}
+	var enc X
+	enc.ID = x.ID
+	enc.Name = x.Name
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	if x.Slice != nil {
+		enc.Slice = make([]replacedInt, len(x.Slice))
+		for k, v := range x.Slice {
+			enc.Slice[k] = replacedInt(v)
+		}
+	}
+	enc.Skip = x.Skip
+	enc.PlainValue = x.PlainValue
+	return bson.Marshal(&enc)
+}
+
+// UnmarshalBSON unmarshals from BSON.
+func (x *X) UnmarshalBSON(input []byte) error {
+	type X struct {
+		ID         *string       `bson:"_id" gencodec:"required"`
+		Name       *string       `bson:"name,omitempty"`
+		Arr        []replacedInt `bson:"arr"`
+		Slice      []replacedInt `bson:"slice"`
+		Skip       *int          `bson:"-"`
+		PlainValue *int          `json:"plain_value"`
+	}
+	var dec X
+	if err := bson.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.ID == nil {
+		return errors.New("missing required field '_id' for X")
+	}
+	x.ID = *dec.ID
+	if dec.Name != nil {
+		x.Name = *dec.Name
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'arr' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = int(v)
+		}
+	}
+	if dec.Slice != nil {
+		x.Slice = make([]int, len(dec.Slice))
+		for k, v := range dec.Slice {
+			x.Slice[k] = int(v)
+		}
+	}
+	if dec.Skip != nil {
+		x.Skip = *dec.Skip
+	}
+	if dec.PlainValue != nil {
+		x.PlainValue = *dec.PlainValue
+	}
+	return nil
+}
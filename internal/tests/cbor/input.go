@@ -0,0 +1,25 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -field-override Xo -formats cbor -out output.go
+
+package cbor
+
+type replacedInt int
+
+type X struct {
+	// Package cbor uses the json tag if there is no cbor tag.
+	Int   int    `json:"number" gencodec:"required"`
+	Name  string `cbor:"name,omitempty"`
+	Arr   [2]int `cbor:"arr"`
+	Slice []int  `cbor:"slice"`
+	Skip  int    `cbor:"-"`
+	Both  int    `json:"jsonBoth" cbor:"both"`
+	Plain int
+}
+
+type Xo struct {
+	Arr   []replacedInt
+	Slice []replacedInt
+}
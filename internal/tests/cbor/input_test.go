@@ -0,0 +1,75 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package cbor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Fields without a cbor tag are encoded using their json tag, or the Go name.
+func TestCBORKeys(t *testing.T) {
+	x := X{Int: 1, Arr: [2]int{2, 3}, Slice: []int{4}, Skip: 5, Both: 6, Plain: 7}
+	enc, err := cbor.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := cbor.Unmarshal(enc, &m); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"number": uint64(1),
+		"arr":    []any{uint64(2), uint64(3)},
+		"slice":  []any{uint64(4)},
+		"both":   uint64(6),
+		"Plain":  uint64(7),
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Fatalf("wrong encoding:\n got %v\nwant %v", m, want)
+	}
+
+	var dec X
+	if err := cbor.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	x.Skip = 0
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+// The json tag of a field with a cbor tag is ignored.
+func TestCBORJSONTagIgnored(t *testing.T) {
+	enc, _ := cbor.Marshal(map[string]any{"number": 1, "jsonBoth": 2})
+	var x X
+	if err := cbor.Unmarshal(enc, &x); err != nil {
+		t.Fatal(err)
+	}
+	if x.Both != 0 {
+		t.Fatalf("field decoded from json key: %+v", x)
+	}
+}
+
+func TestCBORErrors(t *testing.T) {
+	tests := []struct {
+		input map[string]any
+		err   string
+	}{
+		// The required field is named by its json tag.
+		{map[string]any{"name": "x", "int": 1}, "missing required field 'number' for X"},
+		{map[string]any{"number": 1, "arr": []int{1}}, "field 'arr' has wrong length, need 2 items"},
+	}
+	for _, test := range tests {
+		enc, _ := cbor.Marshal(test.input)
+		var x X
+		err := cbor.Unmarshal(enc, &x)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("input %v: wrong error %v\n want %s", test.input, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package cbor
+
+import (
+	"errors"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalCBOR marshals as CBOR.
+func (x X) MarshalCBOR() ([]byte, error) {
+	type X struct {
+		Int   int           `json:"number" gencodec:"required"`
+		Name  string        `cbor:"name,omitempty"`
+		Arr   []replacedInt `cbor:"arr"`
+		Slice []replacedInt `cbor:"slice"`
+		Skip  int           `cbor:"-"`
+		Both  int           `json:"jsonBoth" cbor:"both"`
+		Plain int
+	}
+	var enc X
+	enc.Int = x.Int
+	enc.Name = x.Name
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	if x.Slice != nil {
+		enc.Slice = make([]replacedInt, len(x.Slice))
+		for k, v := range x.Slice {
+			enc.Slice[k] = replacedInt(v)
+		}
+	}
+	enc.Skip = x.Skip
+	enc.Both = x.Both
+	enc.Plain = x.Plain
+	return cbor.Marshal(&enc)
+}
+
+// UnmarshalCBOR unmarshals from CBOR.
+func (x *X) UnmarshalCBOR(input []byte) error {
+	type X struct {
+		Int   *int          `json:"number" gencodec:"required"`
+		Name  *string       `cbor:"name,omitempty"`
+		Arr   []replacedInt `cbor:"arr"`
+		Slice []replacedInt `cbor:"slice"`
+		Skip  *int          `cbor:"-"`
+		Both  *int          `json:"jsonBoth" cbor:"both"`
+		Plain *int
+	}
+	var dec X
+	if err := cbor.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'number' for X")
+	}
+	x.Int = *dec.Int
+	if dec.Name != nil {
+		x.Name = *dec.Name
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'arr' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = int(v)
+		}
+	}
+	if dec.Slice != nil {
+		x.Slice = make([]int, len(dec.Slice))
+		for k, v := range dec.Slice {
+			x.Slice[k] = int(v)
+		}
+	}
+	if dec.Skip != nil {
+		x.Skip = *dec.Skip
+	}
+	if dec.Both != nil {
+		x.Both = *dec.Both
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	return nil
+}
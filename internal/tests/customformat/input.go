@@ -0,0 +1,35 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+// Package customformat tests a format added with RegisterFormat. The output is
+// generated by TestGolden, which registers the "gob" format.
+package customformat
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+type replacedInt int
+
+type X struct {
+	Name  string `gob:"name" gencodec:"required"`
+	Count int
+	Arr   [2]int
+}
+
+type Xo struct {
+	Count replacedInt
+	Arr   []int
+}
+
+func encodeGob(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func decodeGob(input []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(input)).Decode(v)
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package customformat
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestGobRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	x := X{Name: "x", Count: 3, Arr: [2]int{1, 2}}
+	if err := gob.NewEncoder(&buf).Encode(x); err != nil {
+		t.Fatal(err)
+	}
+	var dec X
+	if err := gob.NewDecoder(&buf).Decode(&dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec != x {
+		t.Fatalf("wrong result: %+v", dec)
+	}
+}
+
+func TestGobMissingField(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(X{Count: 1}); err != nil {
+		t.Fatal(err)
+	}
+	var dec X
+	err := gob.NewDecoder(&buf).Decode(&dec)
+	if err == nil || err.Error() != "missing required field 'name' for X" {
+		t.Fatalf("wrong error: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package customformat
+
+import (
+	"errors"
+)
+
+var _ = (*Xo)(nil)
+
+// GobEncode marshals as GOB.
+func (x X) GobEncode() ([]byte, error) {
+	type X struct {
+		Name  string `gob:"name" gencodec:"required"`
+		Count replacedInt
+		Arr   []int
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = replacedInt(x.Count)
+	enc.Arr = x.Arr[:]
+	return encodeGob(&enc)
+}
+
+// GobDecode unmarshals from GOB.
+func (x *X) GobDecode(input []byte) error {
+	type X struct {
+		Name  *string `gob:"name" gencodec:"required"`
+		Count *replacedInt
+		Arr   []int
+	}
+	var dec X
+	if err := decodeGob(input, &dec); err != nil {
+		return err
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = int(*dec.Count)
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'arr' has wrong length, need 2 items")
+		}
+		copy(x.Arr[:], dec.Arr)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type Config -formats json,yaml -out output.go
+
+package defaults
+
+import "time"
+
+type Level string
+
+const DefaultPort = 8080
+
+// dec has the same name as a variable of the unmarshaling methods.
+const dec = 5
+
+type Config struct {
+	Host     string        `gencodec:"default=localhost"`
+	Port     uint16        `gencodec:"default=DefaultPort"`
+	Level    Level         `gencodec:"default=info"`
+	Ratio    float64       `gencodec:"default=0.5"`
+	Enabled  bool          `gencodec:"default=true"`
+	Timeout  time.Duration `gencodec:"default=1m30s"`
+	Tags     string        `gencodec:"default=a,b"`
+	Retries  int           `gencodec:"default=dec"`
+	Name     string        `gencodec:"required"`
+	Internal int           `gencodec:"default=3" json:"-"`
+}
+
+// Types with invalid defaults, used by TestDefaultErrors.
+
+type badOverflow struct {
+	V uint8 `gencodec:"default=300"`
+}
+
+type badConstType struct {
+	V bool `gencodec:"default=DefaultPort"`
+}
+
+type badFieldType struct {
+	V []int `gencodec:"default=1"`
+}
+
+type badRequired struct {
+	V int `gencodec:"required,default=1"`
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package defaults
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var withDefaults = Config{
+	Host:    "localhost",
+	Port:    8080,
+	Level:   "info",
+	Ratio:   0.5,
+	Enabled: true,
+	Timeout: 90 * time.Second,
+	Tags:    "a,b",
+	Retries: dec,
+	Name:    "x",
+}
+
+func TestDefaultsJSON(t *testing.T) {
+	var c Config
+	if err := json.Unmarshal([]byte(`{"Name": "x"}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, withDefaults) {
+		t.Errorf("wrong result:\n got %+v\nwant %+v", c, withDefaults)
+	}
+
+	// Values present in the input, including zero values, replace the default.
+	input := `{"Name": "x", "Host": "", "Port": 1, "Enabled": false, "Timeout": 5}`
+	c = Config{}
+	if err := json.Unmarshal([]byte(input), &c); err != nil {
+		t.Fatal(err)
+	}
+	want := withDefaults
+	want.Host, want.Port, want.Enabled, want.Timeout = "", 1, false, 5
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("wrong result:\n got %+v\nwant %+v", c, want)
+	}
+}
+
+func TestDefaultsYAML(t *testing.T) {
+	var c Config
+	if err := yaml.Unmarshal([]byte("name: x"), &c); err != nil {
+		t.Fatal(err)
+	}
+	want := withDefaults
+	want.Internal = 3
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("wrong result:\n got %+v\nwant %+v", c, want)
+	}
+}
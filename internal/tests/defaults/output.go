@@ -0,0 +1,203 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package defaults
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// MarshalJSON marshals as JSON.
+func (c Config) MarshalJSON() ([]byte, error) {
+	type Config struct {
+		Host     string        `gencodec:"default=localhost"`
+		Port     uint16        `gencodec:"default=DefaultPort"`
+		Level    Level         `gencodec:"default=info"`
+		Ratio    float64       `gencodec:"default=0.5"`
+		Enabled  bool          `gencodec:"default=true"`
+		Timeout  time.Duration `gencodec:"default=1m30s"`
+		Tags     string        `gencodec:"default=a,b"`
+		Retries  int           `gencodec:"default=dec"`
+		Name     string        `gencodec:"required"`
+		Internal int           `gencodec:"default=3" json:"-"`
+	}
+	var enc Config
+	enc.Host = c.Host
+	enc.Port = c.Port
+	enc.Level = c.Level
+	enc.Ratio = c.Ratio
+	enc.Enabled = c.Enabled
+	enc.Timeout = c.Timeout
+	enc.Tags = c.Tags
+	enc.Retries = c.Retries
+	enc.Name = c.Name
+	enc.Internal = c.Internal
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (c *Config) UnmarshalJSON(input []byte) error {
+	type Config struct {
+		Host     *string        `gencodec:"default=localhost"`
+		Port     *uint16        `gencodec:"default=DefaultPort"`
+		Level    *Level         `gencodec:"default=info"`
+		Ratio    *float64       `gencodec:"default=0.5"`
+		Enabled  *bool          `gencodec:"default=true"`
+		Timeout  *time.Duration `gencodec:"default=1m30s"`
+		Tags     *string        `gencodec:"default=a,b"`
+		Retries  *int           `gencodec:"default=dec"`
+		Name     *string        `gencodec:"required"`
+		Internal *int           `gencodec:"default=3" json:"-"`
+	}
+	var dec0 Config
+	if err := json.Unmarshal(input, &dec0); err != nil {
+		return err
+	}
+	if dec0.Host != nil {
+		c.Host = *dec0.Host
+	} else {
+		c.Host = "localhost"
+	}
+	if dec0.Port != nil {
+		c.Port = *dec0.Port
+	} else {
+		c.Port = DefaultPort
+	}
+	if dec0.Level != nil {
+		c.Level = *dec0.Level
+	} else {
+		c.Level = "info"
+	}
+	if dec0.Ratio != nil {
+		c.Ratio = *dec0.Ratio
+	} else {
+		c.Ratio = 0.5
+	}
+	if dec0.Enabled != nil {
+		c.Enabled = *dec0.Enabled
+	} else {
+		c.Enabled = true
+	}
+	if dec0.Timeout != nil {
+		c.Timeout = *dec0.Timeout
+	} else {
+		c.Timeout = 90 * time.Second
+	}
+	if dec0.Tags != nil {
+		c.Tags = *dec0.Tags
+	} else {
+		c.Tags = "a,b"
+	}
+	if dec0.Retries != nil {
+		c.Retries = *dec0.Retries
+	} else {
+		c.Retries = dec
+	}
+	if dec0.Name == nil {
+		return errors.New("missing required field 'name' for Config")
+	}
+	c.Name = *dec0.Name
+	if dec0.Internal != nil {
+		c.Internal = *dec0.Internal
+	}
+	return nil
+}
+
+// MarshalYAML marshals as YAML.
+func (c Config) MarshalYAML() (interface{}, error) {
+	type Config struct {
+		Host     string        `gencodec:"default=localhost"`
+		Port     uint16        `gencodec:"default=DefaultPort"`
+		Level    Level         `gencodec:"default=info"`
+		Ratio    float64       `gencodec:"default=0.5"`
+		Enabled  bool          `gencodec:"default=true"`
+		Timeout  time.Duration `gencodec:"default=1m30s"`
+		Tags     string        `gencodec:"default=a,b"`
+		Retries  int           `gencodec:"default=dec"`
+		Name     string        `gencodec:"required"`
+		Internal int           `gencodec:"default=3" json:"-"`
+	}
+	var enc Config
+	enc.Host = c.Host
+	enc.Port = c.Port
+	enc.Level = c.Level
+	enc.Ratio = c.Ratio
+	enc.Enabled = c.Enabled
+	enc.Timeout = c.Timeout
+	enc.Tags = c.Tags
+	enc.Retries = c.Retries
+	enc.Name = c.Name
+	enc.Internal = c.Internal
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type Config struct {
+		Host     *string        `gencodec:"default=localhost"`
+		Port     *uint16        `gencodec:"default=DefaultPort"`
+		Level    *Level         `gencodec:"default=info"`
+		Ratio    *float64       `gencodec:"default=0.5"`
+		Enabled  *bool          `gencodec:"default=true"`
+		Timeout  *time.Duration `gencodec:"default=1m30s"`
+		Tags     *string        `gencodec:"default=a,b"`
+		Retries  *int           `gencodec:"default=dec"`
+		Name     *string        `gencodec:"required"`
+		Internal *int           `gencodec:"default=3" json:"-"`
+	}
+	var dec0 Config
+	if err := unmarshal(&dec0); err != nil {
+		return err
+	}
+	if dec0.Host != nil {
+		c.Host = *dec0.Host
+	} else {
+		c.Host = "localhost"
+	}
+	if dec0.Port != nil {
+		c.Port = *dec0.Port
+	} else {
+		c.Port = DefaultPort
+	}
+	if dec0.Level != nil {
+		c.Level = *dec0.Level
+	} else {
+		c.Level = "info"
+	}
+	if dec0.Ratio != nil {
+		c.Ratio = *dec0.Ratio
+	} else {
+		c.Ratio = 0.5
+	}
+	if dec0.Enabled != nil {
+		c.Enabled = *dec0.Enabled
+	} else {
+		c.Enabled = true
+	}
+	if dec0.Timeout != nil {
+		c.Timeout = *dec0.Timeout
+	} else {
+		c.Timeout = 90 * time.Second
+	}
+	if dec0.Tags != nil {
+		c.Tags = *dec0.Tags
+	} else {
+		c.Tags = "a,b"
+	}
+	if dec0.Retries != nil {
+		c.Retries = *dec0.Retries
+	} else {
+		c.Retries = dec
+	}
+	if dec0.Name == nil {
+		return errors.New("missing required field 'name' for Config")
+	}
+	c.Name = *dec0.Name
+	if dec0.Internal != nil {
+		c.Internal = *dec0.Internal
+	} else {
+		c.Internal = 3
+	}
+	return nil
+}
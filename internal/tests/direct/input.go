@@ -0,0 +1,54 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X:Xo,Bench -formats json -mode direct -out output.go
+//go:generate go run github.com/fjl/gencodec -type BenchIntermediate -formats json -out intermediate.go
+
+package direct
+
+import (
+	"math/big"
+	"time"
+)
+
+type replacedInt int
+
+type Header struct {
+	Seq  uint64 `json:"seq"`
+	Note string `json:"note,omitempty"`
+}
+
+type X struct {
+	*Header
+	Int      int    `gencodec:"required"`
+	Flag     bool   `json:"flag"`
+	Name     string `json:"name,omitempty"`
+	Ints     []int  `json:"ints,omitempty"`
+	Arr      [2]int `json:"arr"`
+	Big      *big.Int
+	Time     time.Time `json:"time,omitzero"`
+	Map      map[string]int
+	Ignored  int `json:"-"`
+	Esc      int `json:"a<b"`
+	Replaced []int
+}
+
+type Xo struct {
+	Arr      []replacedInt
+	Replaced []replacedInt
+}
+
+// Bench is used by the benchmarks. BenchIntermediate has the same fields, but its
+// methods are generated in the default mode.
+type Bench struct {
+	ID     uint64   `json:"id"`
+	Name   string   `json:"name"`
+	Score  float64  `json:"score"`
+	Ratio  float32  `json:"ratio"`
+	Active bool     `json:"active"`
+	Count  int      `json:"count"`
+	Tags   []string `json:"tags"`
+}
+
+type BenchIntermediate Bench
@@ -0,0 +1,235 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package direct
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDirectRoundTrip(t *testing.T) {
+	x := X{
+		Header:   &Header{Seq: 5},
+		Int:      -1,
+		Flag:     true,
+		Ints:     []int{1, 2},
+		Arr:      [2]int{3, 4},
+		Big:      big.NewInt(7),
+		Time:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Esc:      8,
+		Replaced: []int{9},
+	}
+	enc, err := json.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"seq":5,"Int":-1,"flag":true,"ints":[1,2],"arr":[3,4],"Big":7,"time":"2025-01-02T03:04:05Z","Map":null,"a\u003cb":8,"Replaced":[9]}`
+	if string(enc) != want {
+		t.Fatalf("wrong encoding:\n got %s\nwant %s", enc, want)
+	}
+
+	var dec X
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+func TestDirectUnmarshal(t *testing.T) {
+	var x X
+	input := `{"INT": 1, "unknown": {"a": [1, 2]}, "Name": "n", "seq": 2}`
+	if err := json.Unmarshal([]byte(input), &x); err != nil {
+		t.Fatal(err)
+	}
+	if x.Int != 1 || x.Name != "n" || x.Header == nil || x.Header.Seq != 2 {
+		t.Fatalf("wrong result: %+v", x)
+	}
+
+	if err := json.Unmarshal([]byte(`{"flag": true}`), &x); err == nil {
+		t.Fatal("expected error for missing required field")
+	}
+	if err := json.Unmarshal([]byte(`[1]`), &x); err == nil {
+		t.Fatal("expected error for non-object input")
+	}
+}
+
+// plainBench has the fields of Bench, but no generated methods.
+type plainBench Bench
+
+var benchValue = Bench{
+	ID:     12345,
+	Name:   "a <name> with \"quotes\"",
+	Score:  98.25,
+	Ratio:  0.5,
+	Active: true,
+	Count:  -3,
+	Tags:   []string{"x", "y"},
+}
+
+func TestDirectStrings(t *testing.T) {
+	tests := []Bench{
+		{Name: "<>&\"\\\n\r\t\b\f\x01  \xff"},
+		{Name: "ünïcödé", Score: 1e21, Ratio: 1e-7},
+		{Score: 0.1, Ratio: 3.14},
+		{Score: -1e-7, Ratio: 1e21},
+		{Score: 123456789, Ratio: -0},
+	}
+	for _, b := range tests {
+		want, err := json.Marshal(plainBench(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := b.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != string(want) {
+			t.Errorf("wrong encoding:\n got %s\nwant %s", out, want)
+		}
+
+		var dec, wantDec Bench
+		if err := dec.UnmarshalJSON(out); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(out, (*plainBench)(&wantDec)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(dec, wantDec) {
+			t.Errorf("unmarshal mismatch:\n got %+v\nwant %+v", dec, wantDec)
+		}
+	}
+
+	if _, err := (Bench{Score: math.NaN()}).MarshalJSON(); err == nil {
+		t.Error("expected error for NaN")
+	}
+}
+
+func TestDirectDecodeValues(t *testing.T) {
+	var b Bench
+	input := `{"name": "😀 é", "id": 18446744073709551615, "count": -9223372036854775808, "score": 1E2, "tags": null}`
+	if err := b.UnmarshalJSON([]byte(input)); err != nil {
+		t.Fatal(err)
+	}
+	want := Bench{Name: "😀 é", ID: math.MaxUint64, Count: math.MinInt64, Score: 100}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("wrong result:\n got %+v\nwant %+v", b, want)
+	}
+
+	// Null leaves the field unset.
+	if err := b.UnmarshalJSON([]byte(`{"name": null}`)); err != nil {
+		t.Fatal(err)
+	}
+	if b.Name != want.Name {
+		t.Fatalf("null changed field to %q", b.Name)
+	}
+}
+
+func TestDirectDecodeErrors(t *testing.T) {
+	tests := []string{
+		// Values of the wrong type.
+		`{"id": -1}`,
+		`{"id": 18446744073709551616}`,
+		`{"count": 1.5}`,
+		`{"count": "1"}`,
+		`{"name": 1}`,
+		`{"active": 0}`,
+		`{"ratio": 1e40}`,
+		`{"score": true}`,
+		`{"tags": {}}`,
+		// Syntax errors.
+		``,
+		`{`,
+		`{"id": 1,}`,
+		`{"id" 1}`,
+		`{"id": 01}`,
+		`{"id": 1.}`,
+		`{"id": -}`,
+		`{"name": "\x"}`,
+		`{"name": "\u12"}`,
+		"{\"name\": \"\x01\"}",
+		`{"tags": [1,]}`,
+		`{"tags": [1 2]}`,
+		`{"x": {"a" 1}}`,
+		`{"x": tru}`,
+		`{"x": nul}`,
+		`nul`,
+		// Trailing data.
+		`{} {}`,
+		`{"id": 1}}`,
+		`null x`,
+		// Not an object.
+		`[]`,
+		`"x"`,
+	}
+	for _, input := range tests {
+		var b Bench
+		err := b.UnmarshalJSON([]byte(input))
+		if err == nil {
+			t.Errorf("no error for %#q", input)
+			continue
+		}
+		var p plainBench
+		if json.Unmarshal([]byte(input), &p) == nil {
+			t.Errorf("encoding/json accepts %#q, but got error: %v", input, err)
+		}
+	}
+
+	// Whitespace around the object is allowed.
+	var b Bench
+	if err := b.UnmarshalJSON([]byte(" \t\n{ \"id\" : 1 }\r\n")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	b.Run("direct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := benchValue.MarshalJSON(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("intermediate", func(b *testing.B) {
+		v := BenchIntermediate(benchValue)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := v.MarshalJSON(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	input, err := json.Marshal(plainBench(benchValue))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("direct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v Bench
+			if err := v.UnmarshalJSON(input); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("intermediate", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v BenchIntermediate
+			if err := v.UnmarshalJSON(input); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
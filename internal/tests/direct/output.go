@@ -0,0 +1,355 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package direct
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fjl/gencodec/codec"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	var buf []byte
+	if x.Header != nil {
+		buf = append(buf, `,"seq":`...)
+		buf = strconv.AppendUint(buf, x.Header.Seq, 10)
+	}
+	if x.Header != nil {
+		if x.Header.Note != "" {
+			buf = append(buf, `,"note":`...)
+			buf = codec.AppendJSONString(buf, x.Header.Note)
+		}
+	}
+	buf = append(buf, `,"Int":`...)
+	buf = strconv.AppendInt(buf, int64(x.Int), 10)
+	buf = append(buf, `,"flag":`...)
+	buf = strconv.AppendBool(buf, x.Flag)
+	if x.Name != "" {
+		buf = append(buf, `,"name":`...)
+		buf = codec.AppendJSONString(buf, x.Name)
+	}
+	if len(x.Ints) != 0 {
+		buf = append(buf, `,"ints":`...)
+		if b, err := json.Marshal(&x.Ints); err != nil {
+			return nil, err
+		} else {
+			buf = append(buf, b...)
+		}
+	}
+	var val []replacedInt
+	val = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		val[k] = replacedInt(v)
+	}
+	buf = append(buf, `,"arr":`...)
+	if b0, err := json.Marshal(&val); err != nil {
+		return nil, err
+	} else {
+		buf = append(buf, b0...)
+	}
+	buf = append(buf, `,"Big":`...)
+	if b1, err := json.Marshal(&x.Big); err != nil {
+		return nil, err
+	} else {
+		buf = append(buf, b1...)
+	}
+	if !x.Time.IsZero() {
+		buf = append(buf, `,"time":`...)
+		if b2, err := json.Marshal(&x.Time); err != nil {
+			return nil, err
+		} else {
+			buf = append(buf, b2...)
+		}
+	}
+	buf = append(buf, `,"Map":`...)
+	if b3, err := json.Marshal(&x.Map); err != nil {
+		return nil, err
+	} else {
+		buf = append(buf, b3...)
+	}
+	buf = append(buf, `,"a\u003cb":`...)
+	buf = strconv.AppendInt(buf, int64(x.Esc), 10)
+	var val0 []replacedInt
+	if x.Replaced != nil {
+		val0 = make([]replacedInt, len(x.Replaced))
+		for k, v := range x.Replaced {
+			val0[k] = replacedInt(v)
+		}
+	}
+	buf = append(buf, `,"Replaced":`...)
+	if b4, err := json.Marshal(&val0); err != nil {
+		return nil, err
+	} else {
+		buf = append(buf, b4...)
+	}
+	if len(buf) == 0 {
+		return []byte("{}"), nil
+	}
+	buf[0] = '{'
+	return append(buf, '}'), nil
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input []byte) error {
+	type X struct {
+		Seq      *uint64       `json:"seq"`
+		Note     *string       `json:"note,omitempty"`
+		Int      *int          `gencodec:"required"`
+		Flag     *bool         `json:"flag"`
+		Name     *string       `json:"name,omitempty"`
+		Ints     []int         `json:"ints,omitempty"`
+		Arr      []replacedInt `json:"arr"`
+		Big      *big.Int
+		Time     *time.Time `json:"time,omitzero"`
+		Map      map[string]int
+		Ignored  *int `json:"-"`
+		Esc      *int `json:"a<b"`
+		Replaced []replacedInt
+	}
+	var dec X
+	obj := codec.NewJSONObject(input, "X")
+	for obj.Next() {
+		var err error
+		key := obj.Key()
+		value := obj.Value()
+		switch string(key) {
+		case "seq":
+			err = codec.DecodeJSONUint(value, &dec.Seq)
+		case "note":
+			err = codec.DecodeJSONString(value, &dec.Note)
+		case "Int":
+			err = codec.DecodeJSONInt(value, &dec.Int)
+		case "flag":
+			err = codec.DecodeJSONBool(value, &dec.Flag)
+		case "name":
+			err = codec.DecodeJSONString(value, &dec.Name)
+		case "ints":
+			err = json.Unmarshal(value, &dec.Ints)
+		case "arr":
+			err = json.Unmarshal(value, &dec.Arr)
+		case "Big":
+			err = json.Unmarshal(value, &dec.Big)
+		case "time":
+			err = json.Unmarshal(value, &dec.Time)
+		case "Map":
+			err = json.Unmarshal(value, &dec.Map)
+		case "a<b":
+			err = codec.DecodeJSONInt(value, &dec.Esc)
+		case "Replaced":
+			err = json.Unmarshal(value, &dec.Replaced)
+		default:
+			switch name := string(key); {
+			case strings.EqualFold(name, "seq"):
+				err = codec.DecodeJSONUint(value, &dec.Seq)
+			case strings.EqualFold(name, "note"):
+				err = codec.DecodeJSONString(value, &dec.Note)
+			case strings.EqualFold(name, "Int"):
+				err = codec.DecodeJSONInt(value, &dec.Int)
+			case strings.EqualFold(name, "flag"):
+				err = codec.DecodeJSONBool(value, &dec.Flag)
+			case strings.EqualFold(name, "name"):
+				err = codec.DecodeJSONString(value, &dec.Name)
+			case strings.EqualFold(name, "ints"):
+				err = json.Unmarshal(value, &dec.Ints)
+			case strings.EqualFold(name, "arr"):
+				err = json.Unmarshal(value, &dec.Arr)
+			case strings.EqualFold(name, "Big"):
+				err = json.Unmarshal(value, &dec.Big)
+			case strings.EqualFold(name, "time"):
+				err = json.Unmarshal(value, &dec.Time)
+			case strings.EqualFold(name, "Map"):
+				err = json.Unmarshal(value, &dec.Map)
+			case strings.EqualFold(name, "a<b"):
+				err = codec.DecodeJSONInt(value, &dec.Esc)
+			case strings.EqualFold(name, "Replaced"):
+				err = json.Unmarshal(value, &dec.Replaced)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := obj.Err(); err != nil {
+		return err
+	}
+	if dec.Seq != nil {
+		if x.Header == nil {
+			x.Header = new(Header)
+		}
+		x.Header.Seq = *dec.Seq
+	}
+	if dec.Note != nil {
+		if x.Header == nil {
+			x.Header = new(Header)
+		}
+		x.Header.Note = *dec.Note
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'int' for X")
+	}
+	x.Int = *dec.Int
+	if dec.Flag != nil {
+		x.Flag = *dec.Flag
+	}
+	if dec.Name != nil {
+		x.Name = *dec.Name
+	}
+	if dec.Ints != nil {
+		x.Ints = dec.Ints
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'arr' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = int(v)
+		}
+	}
+	if dec.Big != nil {
+		x.Big = dec.Big
+	}
+	if dec.Time != nil {
+		x.Time = *dec.Time
+	}
+	if dec.Map != nil {
+		x.Map = dec.Map
+	}
+	if dec.Ignored != nil {
+		x.Ignored = *dec.Ignored
+	}
+	if dec.Esc != nil {
+		x.Esc = *dec.Esc
+	}
+	if dec.Replaced != nil {
+		x.Replaced = make([]int, len(dec.Replaced))
+		for k, v := range dec.Replaced {
+			x.Replaced[k] = int(v)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON marshals as JSON.
+func (b Bench) MarshalJSON() ([]byte, error) {
+	var buf []byte
+	buf = append(buf, `,"id":`...)
+	buf = strconv.AppendUint(buf, b.ID, 10)
+	buf = append(buf, `,"name":`...)
+	buf = codec.AppendJSONString(buf, b.Name)
+	buf = append(buf, `,"score":`...)
+	if b0, err := codec.AppendJSONFloat(buf, b.Score, 64); err != nil {
+		return nil, err
+	} else {
+		buf = b0
+	}
+	buf = append(buf, `,"ratio":`...)
+	if b1, err := codec.AppendJSONFloat(buf, float64(b.Ratio), 32); err != nil {
+		return nil, err
+	} else {
+		buf = b1
+	}
+	buf = append(buf, `,"active":`...)
+	buf = strconv.AppendBool(buf, b.Active)
+	buf = append(buf, `,"count":`...)
+	buf = strconv.AppendInt(buf, int64(b.Count), 10)
+	buf = append(buf, `,"tags":`...)
+	if b2, err := json.Marshal(&b.Tags); err != nil {
+		return nil, err
+	} else {
+		buf = append(buf, b2...)
+	}
+	if len(buf) == 0 {
+		return []byte("{}"), nil
+	}
+	buf[0] = '{'
+	return append(buf, '}'), nil
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (b *Bench) UnmarshalJSON(input []byte) error {
+	type Bench struct {
+		ID     *uint64  `json:"id"`
+		Name   *string  `json:"name"`
+		Score  *float64 `json:"score"`
+		Ratio  *float32 `json:"ratio"`
+		Active *bool    `json:"active"`
+		Count  *int     `json:"count"`
+		Tags   []string `json:"tags"`
+	}
+	var dec Bench
+	obj := codec.NewJSONObject(input, "Bench")
+	for obj.Next() {
+		var err error
+		key := obj.Key()
+		value := obj.Value()
+		switch string(key) {
+		case "id":
+			err = codec.DecodeJSONUint(value, &dec.ID)
+		case "name":
+			err = codec.DecodeJSONString(value, &dec.Name)
+		case "score":
+			err = codec.DecodeJSONFloat(value, &dec.Score)
+		case "ratio":
+			err = codec.DecodeJSONFloat(value, &dec.Ratio)
+		case "active":
+			err = codec.DecodeJSONBool(value, &dec.Active)
+		case "count":
+			err = codec.DecodeJSONInt(value, &dec.Count)
+		case "tags":
+			err = json.Unmarshal(value, &dec.Tags)
+		default:
+			switch name := string(key); {
+			case strings.EqualFold(name, "id"):
+				err = codec.DecodeJSONUint(value, &dec.ID)
+			case strings.EqualFold(name, "name"):
+				err = codec.DecodeJSONString(value, &dec.Name)
+			case strings.EqualFold(name, "score"):
+				err = codec.DecodeJSONFloat(value, &dec.Score)
+			case strings.EqualFold(name, "ratio"):
+				err = codec.DecodeJSONFloat(value, &dec.Ratio)
+			case strings.EqualFold(name, "active"):
+				err = codec.DecodeJSONBool(value, &dec.Active)
+			case strings.EqualFold(name, "count"):
+				err = codec.DecodeJSONInt(value, &dec.Count)
+			case strings.EqualFold(name, "tags"):
+				err = json.Unmarshal(value, &dec.Tags)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := obj.Err(); err != nil {
+		return err
+	}
+	if dec.ID != nil {
+		b.ID = *dec.ID
+	}
+	if dec.Name != nil {
+		b.Name = *dec.Name
+	}
+	if dec.Score != nil {
+		b.Score = *dec.Score
+	}
+	if dec.Ratio != nil {
+		b.Ratio = *dec.Ratio
+	}
+	if dec.Active != nil {
+		b.Active = *dec.Active
+	}
+	if dec.Count != nil {
+		b.Count = *dec.Count
+	}
+	if dec.Tags != nil {
+		b.Tags = dec.Tags
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec
+
+package directive
+
+type replacedInt int
+
+// A has methods for all formats.
+//
+//gencodec:generate formats=json,yaml,toml override=ao out=output.go
+type A struct {
+	Int int `gencodec:"required"`
+}
+
+type ao struct {
+	Int replacedInt
+}
+
+type (
+	//gencodec:generate out=output.go
+	B struct {
+		S string
+	}
+
+	// C is not annotated.
+	C struct {
+		S string
+	}
+)
@@ -0,0 +1,113 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package directive
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var _ = (*ao)(nil)
+
+// MarshalJSON marshals as JSON.
+func (a A) MarshalJSON() ([]byte, error) {
+	type A struct {
+		Int replacedInt `gencodec:"required"`
+	}
+	var enc A
+	enc.Int = replacedInt(a.Int)
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (a *A) UnmarshalJSON(input []byte) error {
+	type A struct {
+		Int *replacedInt `gencodec:"required"`
+	}
+	var dec A
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'int' for A")
+	}
+	a.Int = int(*dec.Int)
+	return nil
+}
+
+// MarshalYAML marshals as YAML.
+func (a A) MarshalYAML() (interface{}, error) {
+	type A struct {
+		Int replacedInt `gencodec:"required"`
+	}
+	var enc A
+	enc.Int = replacedInt(a.Int)
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (a *A) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type A struct {
+		Int *replacedInt `gencodec:"required"`
+	}
+	var dec A
+	if err := unmarshal(&dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'int' for A")
+	}
+	a.Int = int(*dec.Int)
+	return nil
+}
+
+// MarshalTOML marshals as TOML.
+func (a A) MarshalTOML() (interface{}, error) {
+	type A struct {
+		Int replacedInt `gencodec:"required"`
+	}
+	var enc A
+	enc.Int = replacedInt(a.Int)
+	return &enc, nil
+}
+
+// UnmarshalTOML unmarshals from TOML.
+func (a *A) UnmarshalTOML(unmarshal func(interface{}) error) error {
+	type A struct {
+		Int *replacedInt `gencodec:"required"`
+	}
+	var dec A
+	if err := unmarshal(&dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'int' for A")
+	}
+	a.Int = int(*dec.Int)
+	return nil
+}
+
+// MarshalJSON marshals as JSON.
+func (b B) MarshalJSON() ([]byte, error) {
+	type B struct {
+		S string
+	}
+	var enc B
+	enc.S = b.S
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (b *B) UnmarshalJSON(input []byte) error {
+	type B struct {
+		S *string
+	}
+	var dec B
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.S != nil {
+		b.S = *dec.S
+	}
+	return nil
+}
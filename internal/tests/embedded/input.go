@@ -0,0 +1,62 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -field-override xOverride -formats json,yaml -out output.go
+
+package embedded
+
+type replacedInt int
+
+type Header struct {
+	ID    int    `gencodec:"required"`
+	Name  string `yaml:"headerName"`
+	Title string
+}
+
+// meta is unexported, but its fields are promoted.
+type meta struct {
+	Version int
+	// Name has a different key than Header.Name. It is renamed in the
+	// intermediate type, but keeps its key in YAML.
+	Name string `json:"name"`
+}
+
+type Extra struct {
+	Note  string
+	Field string // shadowed by X.Field
+	// Label wins against Header.Title in JSON because it is tagged. The YAML
+	// keys of the fields are different, so both are used in YAML.
+	Label string `json:"Title"`
+}
+
+// Tagged is not flattened in JSON because its tag assigns a name.
+type Tagged struct {
+	A int
+}
+
+type X struct {
+	Header
+	meta
+	*Extra
+	Tagged `json:"tagged"`
+	Field  string
+}
+
+type xOverride struct {
+	ID replacedInt
+}
+
+type left struct {
+	Dup int
+}
+
+type right struct {
+	Dup int
+}
+
+// ambiguous has two promoted Dup fields at the same depth. Neither is used.
+type ambiguous struct {
+	left
+	right
+}
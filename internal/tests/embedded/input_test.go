@@ -0,0 +1,90 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package embedded
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// plainX has the same fields as X, but no generated methods.
+type plainX X
+
+func TestEmbeddedJSON(t *testing.T) {
+	x := X{
+		Header: Header{ID: 1, Name: "header", Title: "title"},
+		meta:   meta{Version: 2, Name: "meta"},
+		Extra:  &Extra{Note: "note", Field: "extra", Label: "label"},
+		Tagged: Tagged{A: 3},
+		Field:  "field",
+	}
+	want, err := json.Marshal(plainX(x))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(want) {
+		t.Fatalf("got %#q, want %#q", string(out), string(want))
+	}
+
+	var dec X
+	if err := json.Unmarshal(out, &dec); err != nil {
+		t.Fatal(err)
+	}
+	var wantDec plainX
+	if err := json.Unmarshal(out, &wantDec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, X(wantDec)) {
+		t.Fatalf("unmarshal mismatch: got %+v, want %+v", dec, wantDec)
+	}
+
+	if err := json.Unmarshal([]byte(`{}`), &dec); err == nil {
+		t.Fatal("expected error for missing promoted required field")
+	}
+}
+
+// In YAML, fields are promoted using the yaml tags.
+func TestEmbeddedYAML(t *testing.T) {
+	x := X{
+		Header: Header{ID: 1, Name: "header", Title: "title"},
+		meta:   meta{Version: 2, Name: "meta"},
+		Extra:  &Extra{Note: "note", Field: "extra", Label: "label"},
+		Tagged: Tagged{A: 3},
+		Field:  "field",
+	}
+	want := `id: 1
+headerName: header
+title: title
+version: 2
+name: meta
+note: note
+label: label
+a: 3
+field: field
+`
+	out, err := yaml.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != want {
+		t.Fatalf("got %s\nwant %s", out, want)
+	}
+
+	var dec X
+	if err := yaml.Unmarshal(out, &dec); err != nil {
+		t.Fatal(err)
+	}
+	x.Extra.Field = ""
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("unmarshal mismatch: got %+v, want %+v", dec, x)
+	}
+}
@@ -0,0 +1,190 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package embedded
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var _ = (*xOverride)(nil)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	type X struct {
+		ID      replacedInt `gencodec:"required"`
+		Name    string      `yaml:"headerName"`
+		Title   string      `json:"-"`
+		Version int
+		Name0   string `json:"name" yaml:"name"`
+		Note    string
+		Label   string `json:"Title"`
+		Tagged  Tagged `json:"tagged" yaml:"-"`
+		A       int    `json:"-"`
+		Field   string
+	}
+	var enc X
+	enc.ID = replacedInt(x.Header.ID)
+	enc.Name = x.Header.Name
+	enc.Title = x.Header.Title
+	enc.Version = x.meta.Version
+	enc.Name0 = x.meta.Name
+	if x.Extra != nil {
+		enc.Note = x.Extra.Note
+	}
+	if x.Extra != nil {
+		enc.Label = x.Extra.Label
+	}
+	enc.Tagged = x.Tagged
+	enc.A = x.Tagged.A
+	enc.Field = x.Field
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input []byte) error {
+	type X struct {
+		ID      *replacedInt `gencodec:"required"`
+		Name    *string      `yaml:"headerName"`
+		Title   *string      `json:"-"`
+		Version *int
+		Name0   *string `json:"name" yaml:"name"`
+		Note    *string
+		Label   *string `json:"Title"`
+		Tagged  *Tagged `json:"tagged" yaml:"-"`
+		A       *int    `json:"-"`
+		Field   *string
+	}
+	var dec X
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.ID == nil {
+		return errors.New("missing required field 'iD' for X")
+	}
+	x.Header.ID = int(*dec.ID)
+	if dec.Name != nil {
+		x.Header.Name = *dec.Name
+	}
+	if dec.Title != nil {
+		x.Header.Title = *dec.Title
+	}
+	if dec.Version != nil {
+		x.meta.Version = *dec.Version
+	}
+	if dec.Name0 != nil {
+		x.meta.Name = *dec.Name0
+	}
+	if dec.Note != nil {
+		if x.Extra == nil {
+			x.Extra = new(Extra)
+		}
+		x.Extra.Note = *dec.Note
+	}
+	if dec.Label != nil {
+		if x.Extra == nil {
+			x.Extra = new(Extra)
+		}
+		x.Extra.Label = *dec.Label
+	}
+	if dec.Tagged != nil {
+		x.Tagged = *dec.Tagged
+	}
+	if dec.A != nil {
+		x.Tagged.A = *dec.A
+	}
+	if dec.Field != nil {
+		x.Field = *dec.Field
+	}
+	return nil
+}
+
+// MarshalYAML marshals as YAML.
+func (x X) MarshalYAML() (interface{}, error) {
+	type X struct {
+		ID      replacedInt `gencodec:"required"`
+		Name    string      `yaml:"headerName"`
+		Title   string      `json:"-"`
+		Version int
+		Name0   string `json:"name" yaml:"name"`
+		Note    string
+		Label   string `json:"Title"`
+		Tagged  Tagged `json:"tagged" yaml:"-"`
+		A       int    `json:"-"`
+		Field   string
+	}
+	var enc X
+	enc.ID = replacedInt(x.Header.ID)
+	enc.Name = x.Header.Name
+	enc.Title = x.Header.Title
+	enc.Version = x.meta.Version
+	enc.Name0 = x.meta.Name
+	if x.Extra != nil {
+		enc.Note = x.Extra.Note
+	}
+	if x.Extra != nil {
+		enc.Label = x.Extra.Label
+	}
+	enc.Tagged = x.Tagged
+	enc.A = x.Tagged.A
+	enc.Field = x.Field
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (x *X) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type X struct {
+		ID      *replacedInt `gencodec:"required"`
+		Name    *string      `yaml:"headerName"`
+		Title   *string      `json:"-"`
+		Version *int
+		Name0   *string `json:"name" yaml:"name"`
+		Note    *string
+		Label   *string `json:"Title"`
+		Tagged  *Tagged `json:"tagged" yaml:"-"`
+		A       *int    `json:"-"`
+		Field   *string
+	}
+	var dec X
+	if err := unmarshal(&dec); err != nil {
+		return err
+	}
+	if dec.ID == nil {
+		return errors.New("missing required field 'iD' for X")
+	}
+	x.Header.ID = int(*dec.ID)
+	if dec.Name != nil {
+		x.Header.Name = *dec.Name
+	}
+	if dec.Title != nil {
+		x.Header.Title = *dec.Title
+	}
+	if dec.Version != nil {
+		x.meta.Version = *dec.Version
+	}
+	if dec.Name0 != nil {
+		x.meta.Name = *dec.Name0
+	}
+	if dec.Note != nil {
+		if x.Extra == nil {
+			x.Extra = new(Extra)
+		}
+		x.Extra.Note = *dec.Note
+	}
+	if dec.Label != nil {
+		if x.Extra == nil {
+			x.Extra = new(Extra)
+		}
+		x.Extra.Label = *dec.Label
+	}
+	if dec.Tagged != nil {
+		x.Tagged = *dec.Tagged
+	}
+	if dec.A != nil {
+		x.Tagged.A = *dec.A
+	}
+	if dec.Field != nil {
+		x.Field = *dec.Field
+	}
+	return nil
+}
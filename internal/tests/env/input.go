@@ -0,0 +1,41 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type Config -field-override configOverride -formats env -out output.go
+
+package env
+
+import (
+	"math/big"
+	"net"
+	"time"
+)
+
+type Level uint8
+
+type Config struct {
+	Host     string `gencodec:"required"`
+	HTTPPort int    `env:"PORT"`
+	Debug    bool
+	Level    Level
+	Ratio    float32
+	Timeout  time.Duration
+	Addr     net.IP
+	Peers    []string
+	Ports    [2]uint16
+	Big      *big.Int
+	Ignored  string `env:"-"`
+}
+
+type configOverride struct {
+	Ports []uint16
+	Big   *bigInt
+}
+
+// bigInt decodes decimal numbers through encoding.TextUnmarshaler.
+type bigInt big.Int
+
+func (b *bigInt) UnmarshalText(input []byte) error {
+	return (*big.Int)(b).UnmarshalText(input)
+}
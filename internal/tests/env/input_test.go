@@ -0,0 +1,84 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package env
+
+import (
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func lookupMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestUnmarshalEnv(t *testing.T) {
+	env := map[string]string{
+		"HOST":    "localhost",
+		"PORT":    "8080",
+		"DEBUG":   "true",
+		"LEVEL":   "3",
+		"RATIO":   "0.5",
+		"TIMEOUT": "5s",
+		"ADDR":    "127.0.0.1",
+		"PEERS":   "a,b",
+		"PORTS":   "1,2",
+		"BIG":     "12345678901234567890",
+		"IGNORED": "x",
+	}
+	var cfg Config
+	if err := cfg.UnmarshalEnv(lookupMap(env)); err != nil {
+		t.Fatal(err)
+	}
+	big, _ := new(big.Int).SetString("12345678901234567890", 10)
+	want := Config{
+		Host:     "localhost",
+		HTTPPort: 8080,
+		Debug:    true,
+		Level:    3,
+		Ratio:    0.5,
+		Timeout:  5 * time.Second,
+		Addr:     net.ParseIP("127.0.0.1"),
+		Peers:    []string{"a", "b"},
+		Ports:    [2]uint16{1, 2},
+		Big:      big,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("wrong result:\n got %+v\nwant %+v", cfg, want)
+	}
+}
+
+func TestUnmarshalEnvErrors(t *testing.T) {
+	tests := []struct {
+		env map[string]string
+		err string
+	}{
+		{
+			env: map[string]string{},
+			err: "missing required field 'HOST' for Config",
+		},
+		{
+			env: map[string]string{"HOST": "h", "LEVEL": "300"},
+			err: "invalid value for LEVEL: ",
+		},
+		{
+			env: map[string]string{"HOST": "h", "PORTS": "1"},
+			err: "field 'PORTS' has wrong length, need 2 items",
+		},
+	}
+	for _, test := range tests {
+		var cfg Config
+		err := cfg.UnmarshalEnv(lookupMap(test.env))
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("env %v: wrong error %v, want %q", test.env, err, test.err)
+		}
+	}
+}
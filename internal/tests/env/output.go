@@ -0,0 +1,163 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package env
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ = (*configOverride)(nil)
+
+// UnmarshalEnv unmarshals from environment variables.
+func (c *Config) UnmarshalEnv(lookup func(string) (string, bool)) error {
+	type Config struct {
+		Host     *string `gencodec:"required"`
+		HTTPPort *int    `env:"PORT"`
+		Debug    *bool
+		Level    *Level
+		Ratio    *float32
+		Timeout  *time.Duration
+		Addr     *net.IP
+		Peers    []string
+		Ports    []uint16
+		Big      *bigInt
+		Ignored  *string `env:"-"`
+	}
+	var dec Config
+	if str, ok := lookup("HOST"); ok {
+		var val string
+		val = str
+		dec.Host = &val
+	}
+	if str, ok := lookup("PORT"); ok {
+		var val int
+		v, err := strconv.ParseInt(str, 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("invalid value for PORT: %w", err)
+		}
+		val = int(v)
+		dec.HTTPPort = &val
+	}
+	if str, ok := lookup("DEBUG"); ok {
+		var val bool
+		v0, err := strconv.ParseBool(str)
+		if err != nil {
+			return fmt.Errorf("invalid value for DEBUG: %w", err)
+		}
+		val = v0
+		dec.Debug = &val
+	}
+	if str, ok := lookup("LEVEL"); ok {
+		var val Level
+		v1, err := strconv.ParseUint(str, 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid value for LEVEL: %w", err)
+		}
+		val = Level(v1)
+		dec.Level = &val
+	}
+	if str, ok := lookup("RATIO"); ok {
+		var val float32
+		v2, err := strconv.ParseFloat(str, 32)
+		if err != nil {
+			return fmt.Errorf("invalid value for RATIO: %w", err)
+		}
+		val = float32(v2)
+		dec.Ratio = &val
+	}
+	if str, ok := lookup("TIMEOUT"); ok {
+		var val time.Duration
+		v3, err := time.ParseDuration(str)
+		if err != nil {
+			return fmt.Errorf("invalid value for TIMEOUT: %w", err)
+		}
+		val = v3
+		dec.Timeout = &val
+	}
+	if str, ok := lookup("ADDR"); ok {
+		var val net.IP
+		if err := val.UnmarshalText([]byte(str)); err != nil {
+			return fmt.Errorf("invalid value for ADDR: %w", err)
+		}
+		dec.Addr = &val
+	}
+	if str, ok := lookup("PEERS"); ok {
+		var val []string
+		val = make([]string, 0)
+		if str != "" {
+			for _, s := range strings.Split(str, ",") {
+				var elem string
+				elem = s
+				val = append(val, elem)
+			}
+		}
+		dec.Peers = val
+	}
+	if str, ok := lookup("PORTS"); ok {
+		var val []uint16
+		val = make([]uint16, 0)
+		if str != "" {
+			for _, s0 := range strings.Split(str, ",") {
+				var elem0 uint16
+				v4, err := strconv.ParseUint(s0, 10, 16)
+				if err != nil {
+					return fmt.Errorf("invalid value for PORTS: %w", err)
+				}
+				elem0 = uint16(v4)
+				val = append(val, elem0)
+			}
+		}
+		dec.Ports = val
+	}
+	if str, ok := lookup("BIG"); ok {
+		var val bigInt
+		if err := val.UnmarshalText([]byte(str)); err != nil {
+			return fmt.Errorf("invalid value for BIG: %w", err)
+		}
+		dec.Big = &val
+	}
+	if dec.Host == nil {
+		return errors.New("missing required field 'HOST' for Config")
+	}
+	c.Host = *dec.Host
+	if dec.HTTPPort != nil {
+		c.HTTPPort = *dec.HTTPPort
+	}
+	if dec.Debug != nil {
+		c.Debug = *dec.Debug
+	}
+	if dec.Level != nil {
+		c.Level = *dec.Level
+	}
+	if dec.Ratio != nil {
+		c.Ratio = *dec.Ratio
+	}
+	if dec.Timeout != nil {
+		c.Timeout = *dec.Timeout
+	}
+	if dec.Addr != nil {
+		c.Addr = *dec.Addr
+	}
+	if dec.Peers != nil {
+		c.Peers = dec.Peers
+	}
+	if dec.Ports != nil {
+		if len(dec.Ports) != len(c.Ports) {
+			return errors.New("field 'PORTS' has wrong length, need 2 items")
+		}
+		copy(c.Ports[:], dec.Ports)
+	}
+	if dec.Big != nil {
+		c.Big = (*big.Int)(dec.Big)
+	}
+	if dec.Ignored != nil {
+		c.Ignored = *dec.Ignored
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type Query -field-override queryOverride -formats form -out output.go
+
+package form
+
+import (
+	"math/big"
+	"net"
+	"time"
+)
+
+type Query struct {
+	Search  string        `form:"q" gencodec:"required"`
+	Page    uint          `form:"page,omitempty"`
+	Exact   bool          `form:"exact"`
+	Score   float64       `form:"score,omitempty"`
+	Timeout time.Duration `form:"timeout"`
+	Addr    net.IP        `form:"addr"`
+	Tags    []string      `form:"tag"`
+	Range   [2]int8       `form:"range"`
+	Min     *big.Int      `form:"min"`
+	Limit   *int          `form:"limit"`
+	Ignored string        `form:"-"`
+}
+
+type queryOverride struct {
+	Range []int8
+	Min   *bigInt
+}
+
+// bigInt encodes decimal numbers through encoding.TextMarshaler.
+type bigInt big.Int
+
+func (b *bigInt) MarshalText() ([]byte, error) {
+	return (*big.Int)(b).MarshalText()
+}
+
+func (b *bigInt) UnmarshalText(input []byte) error {
+	return (*big.Int)(b).UnmarshalText(input)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package form
+
+import (
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValuesRoundTrip(t *testing.T) {
+	limit := 10
+	q := Query{
+		Search:  "a b",
+		Page:    2,
+		Exact:   true,
+		Timeout: 5 * time.Second,
+		Addr:    net.ParseIP("127.0.0.1"),
+		Tags:    []string{"x", "y"},
+		Range:   [2]int8{-1, 1},
+		Min:     big.NewInt(12345),
+		Limit:   &limit,
+		Ignored: "ignored",
+	}
+	values := url.Values{"tag": {"old"}}
+	if err := q.EncodeValues(values); err != nil {
+		t.Fatal(err)
+	}
+	want := "addr=127.0.0.1&exact=true&limit=10&min=12345&page=2&q=a+b&range=-1&range=1&tag=x&tag=y&timeout=5s"
+	if enc := values.Encode(); enc != want {
+		t.Fatalf("wrong encoding:\n got %s\nwant %s", enc, want)
+	}
+
+	var dec Query
+	if err := dec.DecodeValues(values); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Min == nil || dec.Min.Cmp(q.Min) != 0 {
+		t.Fatalf("wrong Min %v", dec.Min)
+	}
+	dec.Min, q.Min = nil, nil
+	q.Ignored = ""
+	if !reflect.DeepEqual(dec, q) {
+		t.Fatalf("wrong result:\n got %+v\nwant %+v", dec, q)
+	}
+}
+
+func TestDecodeValuesErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{
+			query: "page=1",
+			err:   "missing required field 'q' for Query",
+		},
+		{
+			query: "q=a&range=300&range=1",
+			err:   "invalid value for range: ",
+		},
+		{
+			query: "q=a&range=1",
+			err:   "field 'range' has wrong length, need 2 items",
+		},
+	}
+	for _, test := range tests {
+		values, err := url.ParseQuery(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var q Query
+		err = q.DecodeValues(values)
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("query %q: wrong error %v, want %q", test.query, err, test.err)
+		}
+	}
+}
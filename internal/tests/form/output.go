@@ -0,0 +1,221 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package form
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var _ = (*queryOverride)(nil)
+
+// EncodeValues marshals as form values.
+func (q Query) EncodeValues(values url.Values) error {
+	type Query struct {
+		Search  string        `form:"q" gencodec:"required"`
+		Page    uint          `form:"page,omitempty"`
+		Exact   bool          `form:"exact"`
+		Score   float64       `form:"score,omitempty"`
+		Timeout time.Duration `form:"timeout"`
+		Addr    net.IP        `form:"addr"`
+		Tags    []string      `form:"tag"`
+		Range   []int8        `form:"range"`
+		Min     *bigInt       `form:"min"`
+		Limit   *int          `form:"limit"`
+		Ignored string        `form:"-"`
+	}
+	var enc Query
+	enc.Search = q.Search
+	enc.Page = q.Page
+	enc.Exact = q.Exact
+	enc.Score = q.Score
+	enc.Timeout = q.Timeout
+	enc.Addr = q.Addr
+	enc.Tags = q.Tags
+	enc.Range = q.Range[:]
+	enc.Min = (*bigInt)(q.Min)
+	enc.Limit = q.Limit
+	enc.Ignored = q.Ignored
+	values.Set("q", enc.Search)
+	if enc.Page != 0 {
+		values.Set("page", strconv.FormatUint(uint64(enc.Page), 10))
+	}
+	values.Set("exact", strconv.FormatBool(enc.Exact))
+	if enc.Score != 0 {
+		values.Set("score", strconv.FormatFloat(enc.Score, 'g', -1, 64))
+	}
+	values.Set("timeout", enc.Timeout.String())
+	if b, err := enc.Addr.MarshalText(); err != nil {
+		return err
+	} else {
+		values.Set("addr", string(b))
+	}
+	values.Del("tag")
+	for _, elem := range enc.Tags {
+		values.Add("tag", elem)
+	}
+	values.Del("range")
+	for _, elem0 := range enc.Range {
+		values.Add("range", strconv.FormatInt(int64(elem0), 10))
+	}
+	if enc.Min != nil {
+		if b0, err := enc.Min.MarshalText(); err != nil {
+			return err
+		} else {
+			values.Set("min", string(b0))
+		}
+	}
+	if enc.Limit != nil {
+		values.Set("limit", strconv.FormatInt(int64(*enc.Limit), 10))
+	}
+	return nil
+}
+
+// DecodeValues unmarshals from form values.
+func (q *Query) DecodeValues(values url.Values) error {
+	type Query struct {
+		Search  *string        `form:"q" gencodec:"required"`
+		Page    *uint          `form:"page,omitempty"`
+		Exact   *bool          `form:"exact"`
+		Score   *float64       `form:"score,omitempty"`
+		Timeout *time.Duration `form:"timeout"`
+		Addr    *net.IP        `form:"addr"`
+		Tags    []string       `form:"tag"`
+		Range   []int8         `form:"range"`
+		Min     *bigInt        `form:"min"`
+		Limit   *int           `form:"limit"`
+		Ignored *string        `form:"-"`
+	}
+	var dec Query
+	if strs := values["q"]; len(strs) != 0 {
+		var val string
+		val = strs[0]
+		dec.Search = &val
+	}
+	if strs := values["page"]; len(strs) != 0 {
+		var val uint
+		v, err := strconv.ParseUint(strs[0], 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("invalid value for page: %w", err)
+		}
+		val = uint(v)
+		dec.Page = &val
+	}
+	if strs := values["exact"]; len(strs) != 0 {
+		var val bool
+		v0, err := strconv.ParseBool(strs[0])
+		if err != nil {
+			return fmt.Errorf("invalid value for exact: %w", err)
+		}
+		val = v0
+		dec.Exact = &val
+	}
+	if strs := values["score"]; len(strs) != 0 {
+		var val float64
+		v1, err := strconv.ParseFloat(strs[0], 64)
+		if err != nil {
+			return fmt.Errorf("invalid value for score: %w", err)
+		}
+		val = v1
+		dec.Score = &val
+	}
+	if strs := values["timeout"]; len(strs) != 0 {
+		var val time.Duration
+		v2, err := time.ParseDuration(strs[0])
+		if err != nil {
+			return fmt.Errorf("invalid value for timeout: %w", err)
+		}
+		val = v2
+		dec.Timeout = &val
+	}
+	if strs := values["addr"]; len(strs) != 0 {
+		var val net.IP
+		if err := val.UnmarshalText([]byte(strs[0])); err != nil {
+			return fmt.Errorf("invalid value for addr: %w", err)
+		}
+		dec.Addr = &val
+	}
+	if strs := values["tag"]; len(strs) != 0 {
+		var val []string
+		val = make([]string, 0)
+		for _, s := range strs {
+			var elem string
+			elem = s
+			val = append(val, elem)
+		}
+		dec.Tags = val
+	}
+	if strs := values["range"]; len(strs) != 0 {
+		var val []int8
+		val = make([]int8, 0)
+		for _, s0 := range strs {
+			var elem0 int8
+			v3, err := strconv.ParseInt(s0, 10, 8)
+			if err != nil {
+				return fmt.Errorf("invalid value for range: %w", err)
+			}
+			elem0 = int8(v3)
+			val = append(val, elem0)
+		}
+		dec.Range = val
+	}
+	if strs := values["min"]; len(strs) != 0 {
+		var val bigInt
+		if err := val.UnmarshalText([]byte(strs[0])); err != nil {
+			return fmt.Errorf("invalid value for min: %w", err)
+		}
+		dec.Min = &val
+	}
+	if strs := values["limit"]; len(strs) != 0 {
+		var val int
+		v4, err := strconv.ParseInt(strs[0], 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("invalid value for limit: %w", err)
+		}
+		val = int(v4)
+		dec.Limit = &val
+	}
+	if dec.Search == nil {
+		return errors.New("missing required field 'q' for Query")
+	}
+	q.Search = *dec.Search
+	if dec.Page != nil {
+		q.Page = *dec.Page
+	}
+	if dec.Exact != nil {
+		q.Exact = *dec.Exact
+	}
+	if dec.Score != nil {
+		q.Score = *dec.Score
+	}
+	if dec.Timeout != nil {
+		q.Timeout = *dec.Timeout
+	}
+	if dec.Addr != nil {
+		q.Addr = *dec.Addr
+	}
+	if dec.Tags != nil {
+		q.Tags = dec.Tags
+	}
+	if dec.Range != nil {
+		if len(dec.Range) != len(q.Range) {
+			return errors.New("field 'range' has wrong length, need 2 items")
+		}
+		copy(q.Range[:], dec.Range)
+	}
+	if dec.Min != nil {
+		q.Min = (*big.Int)(dec.Min)
+	}
+	if dec.Limit != nil {
+		q.Limit = dec.Limit
+	}
+	if dec.Ignored != nil {
+		q.Ignored = *dec.Ignored
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ package funcoverride
 
 import (
 	"encoding/json"
+	"encoding/xml"
 )
 
 var _ = (*Zo)(nil)
@@ -112,3 +113,38 @@ func (z *Z) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	}
 	return nil
 }
+
+// MarshalXML marshals as XML.
+func (z Z) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	type Z struct {
+		S              string `json:"s"`
+		I              int32  `json:"iVal"`
+		Hash           string
+		MultiplyIByTwo int64 `json:"multipliedByTwo"`
+	}
+	var enc Z
+	enc.S = z.S
+	enc.I = z.I
+	enc.Hash = z.Hash()
+	enc.MultiplyIByTwo = int64(z.MultiplyIByTwo())
+	return encoder.EncodeElement(&enc, start)
+}
+
+// UnmarshalXML unmarshals from XML.
+func (z *Z) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	type Z struct {
+		S *string `json:"s"`
+		I *int32  `json:"iVal"`
+	}
+	var dec Z
+	if err := decoder.DecodeElement(&dec, &start); err != nil {
+		return err
+	}
+	if dec.S != nil {
+		z.S = *dec.S
+	}
+	if dec.I != nil {
+		z.I = *dec.I
+	}
+	return nil
+}
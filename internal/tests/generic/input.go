@@ -0,0 +1,30 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type Envelope -field-override envelopeMarshaling -formats json -out output.go
+
+package generic
+
+import (
+	"fmt"
+	"math/big"
+)
+
+type Pair[V any] struct {
+	A, B V
+}
+
+type listOf[T any] []T
+
+type Envelope[T any, M fmt.Stringer] struct {
+	Kind  string `gencodec:"required"`
+	Value T      `gencodec:"required"`
+	List  []T
+	Meta  map[string]M
+	Big   Pair[*big.Int]
+}
+
+type envelopeMarshaling[T any, M fmt.Stringer] struct {
+	List listOf[T]
+}
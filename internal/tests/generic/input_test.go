@@ -0,0 +1,41 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package generic
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type name string
+
+func (n name) String() string { return string(n) }
+
+func TestGenericJSON(t *testing.T) {
+	e := Envelope[int, name]{
+		Kind:  "k",
+		Value: 1,
+		List:  []int{2, 3},
+		Meta:  map[string]name{"a": "b"},
+		Big:   Pair[*big.Int]{big.NewInt(4), big.NewInt(5)},
+	}
+	out, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Envelope[int, name]
+	if err := json.Unmarshal(out, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, e) {
+		t.Fatalf("roundtrip mismatch: got %+v, want %+v", dec, e)
+	}
+
+	if err := json.Unmarshal([]byte(`{"kind":"k"}`), &dec); err == nil {
+		t.Fatal("expected error for missing required field")
+	}
+}
@@ -0,0 +1,63 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package generic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+func _[T any, M fmt.Stringer]() { var _ = (*envelopeMarshaling[T, M])(nil) }
+
+// MarshalJSON marshals as JSON.
+func (e Envelope[T, M]) MarshalJSON() ([]byte, error) {
+	type Envelope struct {
+		Kind  string `gencodec:"required"`
+		Value T      `gencodec:"required"`
+		List  listOf[T]
+		Meta  map[string]M
+		Big   Pair[*big.Int]
+	}
+	var enc Envelope
+	enc.Kind = e.Kind
+	enc.Value = e.Value
+	enc.List = e.List
+	enc.Meta = e.Meta
+	enc.Big = e.Big
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (e *Envelope[T, M]) UnmarshalJSON(input []byte) error {
+	type Envelope struct {
+		Kind  *string `gencodec:"required"`
+		Value *T      `gencodec:"required"`
+		List  *listOf[T]
+		Meta  map[string]M
+		Big   *Pair[*big.Int]
+	}
+	var dec Envelope
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Kind == nil {
+		return errors.New("missing required field 'kind' for Envelope")
+	}
+	e.Kind = *dec.Kind
+	if dec.Value == nil {
+		return errors.New("missing required field 'value' for Envelope")
+	}
+	e.Value = *dec.Value
+	if dec.List != nil {
+		e.List = *dec.List
+	}
+	if dec.Meta != nil {
+		e.Meta = dec.Meta
+	}
+	if dec.Big != nil {
+		e.Big = *dec.Big
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+module github.com/fjl/gencodec/internal/tests
+
+go 1.23.0
+
+replace github.com/fjl/gencodec => ../..
+
+require (
+	github.com/ethereum/go-ethereum v1.13.15
+	github.com/fjl/gencodec v0.0.0-20230517082657-f9840df7b83e
+	github.com/fxamacker/cbor/v2 v2.9.4
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	go.mongodb.org/mongo-driver/v2 v2.0.0
+	google.golang.org/protobuf v1.36.12
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/garslo/gogen v0.0.0-20170306192744-1d203ffc1f61 // indirect
+	github.com/holiman/uint256 v1.2.4 // indirect
+	github.com/hpcloud/tail v1.0.0 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/mod v0.22.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+	golang.org/x/tools v0.29.0 // indirect
+	gopkg.in/fsnotify.v1 v1.4.7 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+)
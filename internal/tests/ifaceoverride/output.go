@@ -4,6 +4,7 @@ package ifaceoverride
 
 import (
 	"encoding/json"
+	"encoding/xml"
 )
 
 var _ = (*cfgOverride)(nil)
@@ -82,3 +83,28 @@ func (c *Cfg) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	}
 	return nil
 }
+
+// MarshalXML marshals as XML.
+func (c Cfg) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	type Cfg struct {
+		Field Iface
+	}
+	var enc Cfg
+	enc.Field = c.Field
+	return encoder.EncodeElement(&enc, start)
+}
+
+// UnmarshalXML unmarshals from XML.
+func (c *Cfg) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	type Cfg struct {
+		Field *Impl
+	}
+	var dec Cfg
+	if err := decoder.DecodeElement(&dec, &start); err != nil {
+		return err
+	}
+	if dec.Field != nil {
+		c.Field = dec.Field
+	}
+	return nil
+}
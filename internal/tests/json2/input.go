@@ -0,0 +1,21 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -field-override Xo -formats json,json2 -out output.go
+
+package json2
+
+type replacedInt int
+
+type X struct {
+	Int   int    `json:"int" gencodec:"required"`
+	Name  string `json:"name,omitempty"`
+	Arr   [2]int `json:"arr"`
+	Slice []int  `json:"slice"`
+}
+
+type Xo struct {
+	Arr   []replacedInt
+	Slice []replacedInt
+}
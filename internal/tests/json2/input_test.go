@@ -0,0 +1,44 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:build go1.27
+
+package json2
+
+import (
+	"encoding/json/v2"
+	"reflect"
+	"testing"
+)
+
+func TestJSONv2RoundTrip(t *testing.T) {
+	x := X{Int: 1, Arr: [2]int{2, 3}, Slice: []int{4}}
+	enc, err := json.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"int":1,"arr":[2,3],"slice":[4]}`
+	if string(enc) != want {
+		t.Fatalf("wrong encoding:\n got %s\nwant %s", enc, want)
+	}
+	var dec X
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+func TestJSONv2Errors(t *testing.T) {
+	var x X
+	err := json.Unmarshal([]byte(`{"name": "x"}`), &x)
+	if err == nil {
+		t.Fatal("expected error for missing required field")
+	}
+	err = json.Unmarshal([]byte(`{"int": 1, "arr": [1]}`), &x)
+	if err == nil {
+		t.Fatal("expected error for wrong array length")
+	}
+}
@@ -0,0 +1,132 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package json2
+
+import (
+	"encoding/json"
+	"encoding/json/jsontext"
+	json0 "encoding/json/v2"
+	"errors"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	type X struct {
+		Int   int           `json:"int" gencodec:"required"`
+		Name  string        `json:"name,omitempty"`
+		Arr   []replacedInt `json:"arr"`
+		Slice []replacedInt `json:"slice"`
+	}
+	var enc X
+	enc.Int = x.Int
+	enc.Name = x.Name
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	if x.Slice != nil {
+		enc.Slice = make([]replacedInt, len(x.Slice))
+		for k, v := range x.Slice {
+			enc.Slice[k] = replacedInt(v)
+		}
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input []byte) error {
+	type X struct {
+		Int   *int          `json:"int" gencodec:"required"`
+		Name  *string       `json:"name,omitempty"`
+		Arr   []replacedInt `json:"arr"`
+		Slice []replacedInt `json:"slice"`
+	}
+	var dec X
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'int' for X")
+	}
+	x.Int = *dec.Int
+	if dec.Name != nil {
+		x.Name = *dec.Name
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'arr' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = int(v)
+		}
+	}
+	if dec.Slice != nil {
+		x.Slice = make([]int, len(dec.Slice))
+		for k, v := range dec.Slice {
+			x.Slice[k] = int(v)
+		}
+	}
+	return nil
+}
+
+// MarshalJSONTo marshals as JSON.
+func (x X) MarshalJSONTo(encoder *jsontext.Encoder) error {
+	type X struct {
+		Int   int           `json:"int" gencodec:"required"`
+		Name  string        `json:"name,omitempty"`
+		Arr   []replacedInt `json:"arr"`
+		Slice []replacedInt `json:"slice"`
+	}
+	var enc X
+	enc.Int = x.Int
+	enc.Name = x.Name
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	if x.Slice != nil {
+		enc.Slice = make([]replacedInt, len(x.Slice))
+		for k, v := range x.Slice {
+			enc.Slice[k] = replacedInt(v)
+		}
+	}
+	return json0.MarshalEncode(encoder, &enc)
+}
+
+// UnmarshalJSONFrom unmarshals from JSON.
+func (x *X) UnmarshalJSONFrom(decoder *jsontext.Decoder) error {
+	type X struct {
+		Int   *int          `json:"int" gencodec:"required"`
+		Name  *string       `json:"name,omitempty"`
+		Arr   []replacedInt `json:"arr"`
+		Slice []replacedInt `json:"slice"`
+	}
+	var dec X
+	if err := json0.UnmarshalDecode(decoder, &dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'int' for X")
+	}
+	x.Int = *dec.Int
+	if dec.Name != nil {
+		x.Name = *dec.Name
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'arr' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = int(v)
+		}
+	}
+	if dec.Slice != nil {
+		x.Slice = make([]int, len(dec.Slice))
+		for k, v := range dec.Slice {
+			x.Slice[k] = int(v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -formats json,yaml,form -out output.go
+
+package keyalias
+
+type X struct {
+	Name      string   `json:"name" yaml:"name" form:"name" gencodec:"required,alias=title"`
+	Timeout   int      `json:"timeout,omitempty" yaml:"timeout,omitempty" form:"timeout" gencodec:"default=30,alias=timeoutSeconds"`
+	Tags      []string `json:"tags" yaml:"tags" form:"tag" gencodec:"alias=labels"`
+	TagsAlias int      `json:"tagsAlias"`
+}
+
+// Types with clashing aliases, used by TestAliasErrors.
+
+type badAliasKey struct {
+	Name  string `json:"name" yaml:"title" gencodec:"alias=title"`
+	Title string `json:"title" yaml:"heading"`
+}
+
+type badAliasOwnKey struct {
+	Name string `json:"name" yaml:"title" gencodec:"alias=title"`
+}
+
+type badAliasTwice struct {
+	Name  string `json:"name" gencodec:"alias=label"`
+	Title string `json:"title" gencodec:"alias=label"`
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package keyalias
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestAliasJSON(t *testing.T) {
+	var x X
+	if err := json.Unmarshal([]byte(`{"title":"a","timeoutSeconds":5,"labels":["b"]}`), &x); err != nil {
+		t.Fatal(err)
+	}
+	want := X{Name: "a", Timeout: 5, Tags: []string{"b"}}
+	if !reflect.DeepEqual(x, want) {
+		t.Fatalf("wrong result %+v", x)
+	}
+	// Only the current name is written.
+	enc, err := json.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"a","timeout":5,"tags":["b"],"tagsAlias":0}`; string(enc) != want {
+		t.Fatalf("wrong encoding:\n got %s\nwant %s", enc, want)
+	}
+
+	err = json.Unmarshal([]byte(`{"name":"a","title":"b"}`), &x)
+	checkError(t, err, "field 'name' and its alias 'title' are both set for X")
+}
+
+func TestAliasYAML(t *testing.T) {
+	var x X
+	if err := yaml.Unmarshal([]byte("title: a\n"), &x); err != nil {
+		t.Fatal(err)
+	}
+	if want := (X{Name: "a", Timeout: 30}); !reflect.DeepEqual(x, want) {
+		t.Fatalf("wrong result %+v", x)
+	}
+	err := yaml.Unmarshal([]byte("name: a\ntimeout: 1\ntimeoutSeconds: 2\n"), &x)
+	checkError(t, err, "field 'timeout' and its alias 'timeoutSeconds' are both set for X")
+}
+
+func TestAliasForm(t *testing.T) {
+	var x X
+	if err := x.DecodeValues(url.Values{"name": {"a"}, "labels": {"b", "c"}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"b", "c"}; !reflect.DeepEqual(x.Tags, want) {
+		t.Fatalf("wrong tags %q", x.Tags)
+	}
+	err := x.DecodeValues(url.Values{"name": {"a"}, "tag": {"b"}, "labels": {"c"}})
+	checkError(t, err, "field 'tag' and its alias 'labels' are both set for X")
+}
+
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("no error, want %q", want)
+	}
+	if err.Error() != want {
+		t.Fatalf("wrong error %q\n want %q", err, want)
+	}
+}
@@ -0,0 +1,273 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package keyalias
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	type X struct {
+		Name      string   `json:"name" yaml:"name" form:"name" gencodec:"required,alias=title"`
+		Timeout   int      `json:"timeout,omitempty" yaml:"timeout,omitempty" form:"timeout" gencodec:"default=30,alias=timeoutSeconds"`
+		Tags      []string `json:"tags" yaml:"tags" form:"tag" gencodec:"alias=labels"`
+		TagsAlias int      `json:"tagsAlias"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Timeout = x.Timeout
+	enc.Tags = x.Tags
+	enc.TagsAlias = x.TagsAlias
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input []byte) error {
+	type X struct {
+		Name         *string  `json:"name" yaml:"name" form:"name" gencodec:"required,alias=title"`
+		NameAlias    *string  `json:"title" yaml:"title" form:"title"`
+		Timeout      *int     `json:"timeout,omitempty" yaml:"timeout,omitempty" form:"timeout" gencodec:"default=30,alias=timeoutSeconds"`
+		TimeoutAlias *int     `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty" form:"timeoutSeconds"`
+		Tags         []string `json:"tags" yaml:"tags" form:"tag" gencodec:"alias=labels"`
+		TagsAlias2   []string `json:"labels" yaml:"labels" form:"labels"`
+		TagsAlias    *int     `json:"tagsAlias"`
+	}
+	var dec X
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.NameAlias != nil {
+		if dec.Name != nil {
+			return errors.New("field 'name' and its alias 'title' are both set for X")
+		}
+		dec.Name = dec.NameAlias
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.TimeoutAlias != nil {
+		if dec.Timeout != nil {
+			return errors.New("field 'timeout' and its alias 'timeoutSeconds' are both set for X")
+		}
+		dec.Timeout = dec.TimeoutAlias
+	}
+	if dec.Timeout != nil {
+		x.Timeout = *dec.Timeout
+	} else {
+		x.Timeout = 30
+	}
+	if dec.TagsAlias2 != nil {
+		if dec.Tags != nil {
+			return errors.New("field 'tags' and its alias 'labels' are both set for X")
+		}
+		dec.Tags = dec.TagsAlias2
+	}
+	if dec.Tags != nil {
+		x.Tags = dec.Tags
+	}
+	if dec.TagsAlias != nil {
+		x.TagsAlias = *dec.TagsAlias
+	}
+	return nil
+}
+
+// MarshalYAML marshals as YAML.
+func (x X) MarshalYAML() (interface{}, error) {
+	type X struct {
+		Name      string   `json:"name" yaml:"name" form:"name" gencodec:"required,alias=title"`
+		Timeout   int      `json:"timeout,omitempty" yaml:"timeout,omitempty" form:"timeout" gencodec:"default=30,alias=timeoutSeconds"`
+		Tags      []string `json:"tags" yaml:"tags" form:"tag" gencodec:"alias=labels"`
+		TagsAlias int      `json:"tagsAlias"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Timeout = x.Timeout
+	enc.Tags = x.Tags
+	enc.TagsAlias = x.TagsAlias
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (x *X) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type X struct {
+		Name         *string  `json:"name" yaml:"name" form:"name" gencodec:"required,alias=title"`
+		NameAlias    *string  `json:"title" yaml:"title" form:"title"`
+		Timeout      *int     `json:"timeout,omitempty" yaml:"timeout,omitempty" form:"timeout" gencodec:"default=30,alias=timeoutSeconds"`
+		TimeoutAlias *int     `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty" form:"timeoutSeconds"`
+		Tags         []string `json:"tags" yaml:"tags" form:"tag" gencodec:"alias=labels"`
+		TagsAlias2   []string `json:"labels" yaml:"labels" form:"labels"`
+		TagsAlias    *int     `json:"tagsAlias"`
+	}
+	var dec X
+	if err := unmarshal(&dec); err != nil {
+		return err
+	}
+	if dec.NameAlias != nil {
+		if dec.Name != nil {
+			return errors.New("field 'name' and its alias 'title' are both set for X")
+		}
+		dec.Name = dec.NameAlias
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.TimeoutAlias != nil {
+		if dec.Timeout != nil {
+			return errors.New("field 'timeout' and its alias 'timeoutSeconds' are both set for X")
+		}
+		dec.Timeout = dec.TimeoutAlias
+	}
+	if dec.Timeout != nil {
+		x.Timeout = *dec.Timeout
+	} else {
+		x.Timeout = 30
+	}
+	if dec.TagsAlias2 != nil {
+		if dec.Tags != nil {
+			return errors.New("field 'tags' and its alias 'labels' are both set for X")
+		}
+		dec.Tags = dec.TagsAlias2
+	}
+	if dec.Tags != nil {
+		x.Tags = dec.Tags
+	}
+	if dec.TagsAlias != nil {
+		x.TagsAlias = *dec.TagsAlias
+	}
+	return nil
+}
+
+// EncodeValues marshals as form values.
+func (x X) EncodeValues(values url.Values) error {
+	type X struct {
+		Name      string   `json:"name" yaml:"name" form:"name" gencodec:"required,alias=title"`
+		Timeout   int      `json:"timeout,omitempty" yaml:"timeout,omitempty" form:"timeout" gencodec:"default=30,alias=timeoutSeconds"`
+		Tags      []string `json:"tags" yaml:"tags" form:"tag" gencodec:"alias=labels"`
+		TagsAlias int      `json:"tagsAlias"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Timeout = x.Timeout
+	enc.Tags = x.Tags
+	enc.TagsAlias = x.TagsAlias
+	values.Set("name", enc.Name)
+	values.Set("timeout", strconv.FormatInt(int64(enc.Timeout), 10))
+	values.Del("tag")
+	for _, elem := range enc.Tags {
+		values.Add("tag", elem)
+	}
+	values.Set("tagsAlias", strconv.FormatInt(int64(enc.TagsAlias), 10))
+	return nil
+}
+
+// DecodeValues unmarshals from form values.
+func (x *X) DecodeValues(values url.Values) error {
+	type X struct {
+		Name         *string  `json:"name" yaml:"name" form:"name" gencodec:"required,alias=title"`
+		NameAlias    *string  `json:"title" yaml:"title" form:"title"`
+		Timeout      *int     `json:"timeout,omitempty" yaml:"timeout,omitempty" form:"timeout" gencodec:"default=30,alias=timeoutSeconds"`
+		TimeoutAlias *int     `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty" form:"timeoutSeconds"`
+		Tags         []string `json:"tags" yaml:"tags" form:"tag" gencodec:"alias=labels"`
+		TagsAlias2   []string `json:"labels" yaml:"labels" form:"labels"`
+		TagsAlias    *int     `json:"tagsAlias"`
+	}
+	var dec X
+	if strs := values["name"]; len(strs) != 0 {
+		var val string
+		val = strs[0]
+		dec.Name = &val
+	}
+	if strs := values["title"]; len(strs) != 0 {
+		var val string
+		val = strs[0]
+		dec.NameAlias = &val
+	}
+	if strs := values["timeout"]; len(strs) != 0 {
+		var val int
+		v, err := strconv.ParseInt(strs[0], 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("invalid value for timeout: %w", err)
+		}
+		val = int(v)
+		dec.Timeout = &val
+	}
+	if strs := values["timeoutSeconds"]; len(strs) != 0 {
+		var val int
+		v0, err := strconv.ParseInt(strs[0], 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("invalid value for timeoutSeconds: %w", err)
+		}
+		val = int(v0)
+		dec.TimeoutAlias = &val
+	}
+	if strs := values["tag"]; len(strs) != 0 {
+		var val []string
+		val = make([]string, 0)
+		for _, s := range strs {
+			var elem string
+			elem = s
+			val = append(val, elem)
+		}
+		dec.Tags = val
+	}
+	if strs := values["labels"]; len(strs) != 0 {
+		var val []string
+		val = make([]string, 0)
+		for _, s0 := range strs {
+			var elem0 string
+			elem0 = s0
+			val = append(val, elem0)
+		}
+		dec.TagsAlias2 = val
+	}
+	if strs := values["tagsAlias"]; len(strs) != 0 {
+		var val int
+		v1, err := strconv.ParseInt(strs[0], 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("invalid value for tagsAlias: %w", err)
+		}
+		val = int(v1)
+		dec.TagsAlias = &val
+	}
+	if dec.NameAlias != nil {
+		if dec.Name != nil {
+			return errors.New("field 'name' and its alias 'title' are both set for X")
+		}
+		dec.Name = dec.NameAlias
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.TimeoutAlias != nil {
+		if dec.Timeout != nil {
+			return errors.New("field 'timeout' and its alias 'timeoutSeconds' are both set for X")
+		}
+		dec.Timeout = dec.TimeoutAlias
+	}
+	if dec.Timeout != nil {
+		x.Timeout = *dec.Timeout
+	} else {
+		x.Timeout = 30
+	}
+	if dec.TagsAlias2 != nil {
+		if dec.Tags != nil {
+			return errors.New("field 'tag' and its alias 'labels' are both set for X")
+		}
+		dec.Tags = dec.TagsAlias2
+	}
+	if dec.Tags != nil {
+		x.Tags = dec.Tags
+	}
+	if dec.TagsAlias != nil {
+		x.TagsAlias = *dec.TagsAlias
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -field-override Xo -formats msgpack -out output.go
+
+package msgpack
+
+type replacedInt int
+
+type X struct {
+	Int   int    `msgpack:"int" gencodec:"required"`
+	Name  string `msgpack:"name,omitempty"`
+	Arr   [2]int `msgpack:"arr"`
+	Slice []int  `msgpack:"slice"`
+	Skip  int    `msgpack:"-"`
+	// Package msgpack uses the Go name as the key, and ignores json tags.
+	Plain int `json:"plain"`
+}
+
+type Xo struct {
+	Arr   []replacedInt
+	Slice []replacedInt
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package msgpack
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestMsgpackKeys(t *testing.T) {
+	x := X{Int: 1, Arr: [2]int{2, 3}, Slice: []int{4}, Skip: 5, Plain: 6}
+	enc, err := msgpack.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := msgpack.Unmarshal(enc, &m); err != nil {
+		t.Fatal(err)
+	}
+	// Name is omitted because it is empty, Skip is ignored and Plain uses the Go name.
+	want := map[string]any{
+		"int":   int8(1),
+		"arr":   []any{int8(2), int8(3)},
+		"slice": []any{int8(4)},
+		"Plain": int8(6),
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Fatalf("wrong encoding:\n got %#v\nwant %#v", m, want)
+	}
+
+	var dec X
+	if err := msgpack.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	x.Skip = 0
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+func TestMsgpackOmitEmpty(t *testing.T) {
+	enc, err := msgpack.Marshal(X{Name: "n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := msgpack.Unmarshal(enc, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["name"] != "n" {
+		t.Fatalf("non-empty name not encoded: %#v", m)
+	}
+}
+
+func TestMsgpackErrors(t *testing.T) {
+	tests := []struct {
+		input map[string]any
+		err   string
+	}{
+		{map[string]any{"name": "x", "Int": 1}, "missing required field 'int' for X"},
+		{map[string]any{"int": 1, "arr": []int{1}}, "field 'arr' has wrong length, need 2 items"},
+	}
+	for _, test := range tests {
+		enc, _ := msgpack.Marshal(test.input)
+		var x X
+		err := msgpack.Unmarshal(enc, &x)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("input %v: wrong error %v\n want %s", test.input, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package msgpack
+
+import (
+	"errors"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var _ = (*Xo)(nil)
+
+// EncodeMsgpack marshals as MessagePack.
+func (x X) EncodeMsgpack(encoder *msgpack.Encoder) error {
+	type X struct {
+		Int   int           `msgpack:"int" gencodec:"required"`
+		Name  string        `msgpack:"name,omitempty"`
+		Arr   []replacedInt `msgpack:"arr"`
+		Slice []replacedInt `msgpack:"slice"`
+		Skip  int           `msgpack:"-"`
+		Plain int           `json:"plain"`
+	}
+	var enc X
+	enc.Int = x.Int
+	enc.Name = x.Name
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	if x.Slice != nil {
+		enc.Slice = make([]replacedInt, len(x.Slice))
+		for k, v := range x.Slice {
+			enc.Slice[k] = replacedInt(v)
+		}
+	}
+	enc.Skip = x.Skip
+	enc.Plain = x.Plain
+	return encoder.Encode(&enc)
+}
+
+// DecodeMsgpack unmarshals from MessagePack.
+func (x *X) DecodeMsgpack(decoder *msgpack.Decoder) error {
+	type X struct {
+		Int   *int          `msgpack:"int" gencodec:"required"`
+		Name  *string       `msgpack:"name,omitempty"`
+		Arr   []replacedInt `msgpack:"arr"`
+		Slice []replacedInt `msgpack:"slice"`
+		Skip  *int          `msgpack:"-"`
+		Plain *int          `json:"plain"`
+	}
+	var dec X
+	if err := decoder.Decode(&dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'int' for X")
+	}
+	x.Int = *dec.Int
+	if dec.Name != nil {
+		x.Name = *dec.Name
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'arr' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = int(v)
+		}
+	}
+	if dec.Slice != nil {
+		x.Slice = make([]int, len(dec.Slice))
+		for k, v := range dec.Slice {
+			x.Slice[k] = int(v)
+		}
+	}
+	if dec.Skip != nil {
+		x.Skip = *dec.Skip
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type A:ao,B -formats json -out output.go
+
+package multitype
+
+import "math/big"
+
+type replacedInt int
+
+type A struct {
+	Int int `gencodec:"required"`
+	B   B
+}
+
+type ao struct {
+	Int replacedInt
+}
+
+type B struct {
+	Big *big.Int
+}
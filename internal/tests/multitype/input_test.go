@@ -0,0 +1,10 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package multitype
+
+// testOnly is declared in a test file to check that it can be selected with -type.
+type testOnly struct {
+	A A
+}
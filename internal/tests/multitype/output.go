@@ -0,0 +1,68 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package multitype
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+var _ = (*ao)(nil)
+
+// MarshalJSON marshals as JSON.
+func (a A) MarshalJSON() ([]byte, error) {
+	type A struct {
+		Int replacedInt `gencodec:"required"`
+		B   B
+	}
+	var enc A
+	enc.Int = replacedInt(a.Int)
+	enc.B = a.B
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (a *A) UnmarshalJSON(input []byte) error {
+	type A struct {
+		Int *replacedInt `gencodec:"required"`
+		B   *B
+	}
+	var dec A
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return errors.New("missing required field 'int' for A")
+	}
+	a.Int = int(*dec.Int)
+	if dec.B != nil {
+		a.B = *dec.B
+	}
+	return nil
+}
+
+// MarshalJSON marshals as JSON.
+func (b B) MarshalJSON() ([]byte, error) {
+	type B0 struct {
+		Big *big.Int
+	}
+	var enc B0
+	enc.Big = b.Big
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (b *B) UnmarshalJSON(input []byte) error {
+	type B0 struct {
+		Big *big.Int
+	}
+	var dec B0
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Big != nil {
+		b.Big = dec.Big
+	}
+	return nil
+}
@@ -2,7 +2,7 @@
 // Use of this source code is governed by the MIT license,
 // which can be found in the LICENSE file.
 
-//go:generate go run github.com/fjl/gencodec -type Y -field-override yo -formats json,yaml,toml -out output.go
+//go:generate go run github.com/fjl/gencodec -type Y -field-override yo -formats json,yaml,toml,xml -out output.go
 
 package nameclash
 
@@ -4,6 +4,7 @@ package nameclash
 
 import (
 	"encoding/json"
+	"encoding/xml"
 
 	errors0 "github.com/fjl/gencodec/internal/clasherrors"
 	json0 "github.com/fjl/gencodec/internal/clashjson"
@@ -157,3 +158,52 @@ func (y *Y) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	}
 	return nil
 }
+
+// MarshalXML marshals as XML.
+func (y Y) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	type Y struct {
+		Foo    json0.Foo
+		Foo2   json0.Foo
+		Bar    errors0.Foo
+		Gazonk YJSON
+		Over   enc
+	}
+	var enc0 Y
+	enc0.Foo = y.Foo
+	enc0.Foo2 = y.Foo2
+	enc0.Bar = y.Bar
+	enc0.Gazonk = y.Gazonk
+	enc0.Over = enc(y.Over)
+	return encoder.EncodeElement(&enc0, start)
+}
+
+// UnmarshalXML unmarshals from XML.
+func (y *Y) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	type Y struct {
+		Foo    *json0.Foo
+		Foo2   *json0.Foo
+		Bar    *errors0.Foo
+		Gazonk *YJSON
+		Over   *enc
+	}
+	var dec Y
+	if err := decoder.DecodeElement(&dec, &start); err != nil {
+		return err
+	}
+	if dec.Foo != nil {
+		y.Foo = *dec.Foo
+	}
+	if dec.Foo2 != nil {
+		y.Foo2 = *dec.Foo2
+	}
+	if dec.Bar != nil {
+		y.Bar = *dec.Bar
+	}
+	if dec.Gazonk != nil {
+		y.Gazonk = *dec.Gazonk
+	}
+	if dec.Over != nil {
+		y.Over = int(*dec.Over)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ package omitempty
 
 import (
 	"encoding/json"
+	"encoding/xml"
 )
 
 var _ = (*Xo)(nil)
@@ -82,3 +83,28 @@ func (x *X) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	}
 	return nil
 }
+
+// MarshalXML marshals as XML.
+func (x X) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	type X struct {
+		Int replacedInt `json:",omitempty"`
+	}
+	var enc X
+	enc.Int = replacedInt(x.Int)
+	return encoder.EncodeElement(&enc, start)
+}
+
+// UnmarshalXML unmarshals from XML.
+func (x *X) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	type X struct {
+		Int *replacedInt `json:",omitempty"`
+	}
+	var dec X
+	if err := decoder.DecodeElement(&dec, &start); err != nil {
+		return err
+	}
+	if dec.Int != nil {
+		x.Int = int(*dec.Int)
+	}
+	return nil
+}
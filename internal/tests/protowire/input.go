@@ -0,0 +1,38 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X:Xo,Y -formats protowire -out output.go -proto-out output.proto
+
+package protowire
+
+import "time"
+
+type replacedUint uint
+
+type X struct {
+	ID      uint64        `proto:"1" gencodec:"required"`
+	Name    string        `proto:"2"`
+	Flag    bool          `proto:"3"`
+	Small   int8          `proto:"4"`
+	Ratio   float32       `proto:"5"`
+	Score   float64       `proto:"6"`
+	Timeout time.Duration `proto:"7"`
+	Data    []byte        `proto:"8"`
+	Hash    [2]uint       `proto:"9"`
+	Nums    []int32       `proto:"10"`
+	Names   []string      `proto:"11"`
+	Opt     *uint32       `proto:"12"`
+	Child   Y             `proto:"13"`
+	Next    *Y            `proto:"14"`
+	List    []*Y          `proto:"15"`
+	Skip    int           `proto:"-"`
+}
+
+type Xo struct {
+	Hash []replacedUint
+}
+
+type Y struct {
+	S string `proto:"1"`
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package protowire
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestRoundTrip(t *testing.T) {
+	opt := uint32(7)
+	x := X{
+		ID:      1,
+		Name:    "name",
+		Flag:    true,
+		Small:   -3,
+		Ratio:   0.5,
+		Score:   1.25,
+		Timeout: time.Second,
+		Data:    []byte{1, 2},
+		Hash:    [2]uint{3, 4},
+		Nums:    []int32{-1, 300},
+		Names:   []string{"a", ""},
+		Opt:     &opt,
+		Child:   Y{S: "child"},
+		Next:    &Y{S: "next"},
+		List:    []*Y{{S: "1"}, {}},
+	}
+	enc, err := x.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec X
+	if err := dec.UnmarshalProto(enc); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("wrong result:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	// Zero values are not encoded, except for required fields.
+	enc, err := X{Small: -1, Hash: [2]uint{1, 2}}.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []byte
+	want = protowire.AppendTag(want, 1, protowire.VarintType)
+	want = protowire.AppendVarint(want, 0)
+	want = protowire.AppendTag(want, 4, protowire.VarintType)
+	want = protowire.AppendVarint(want, 1<<64-1)
+	want = protowire.AppendTag(want, 9, protowire.BytesType)
+	want = protowire.AppendBytes(want, []byte{1, 2})
+	want = protowire.AppendTag(want, 13, protowire.BytesType)
+	want = protowire.AppendBytes(want, nil)
+	if !reflect.DeepEqual(enc, want) {
+		t.Fatalf("wrong encoding:\n got %x\nwant %x", enc, want)
+	}
+}
+
+func TestDecoding(t *testing.T) {
+	// Repeated scalars are also accepted without packing. Unknown fields are skipped.
+	var input []byte
+	input = protowire.AppendTag(input, 1, protowire.VarintType)
+	input = protowire.AppendVarint(input, 5)
+	input = protowire.AppendTag(input, 10, protowire.VarintType)
+	input = protowire.AppendVarint(input, 1)
+	input = protowire.AppendTag(input, 100, protowire.BytesType)
+	input = protowire.AppendString(input, "unknown")
+	input = protowire.AppendTag(input, 10, protowire.BytesType)
+	input = protowire.AppendBytes(input, []byte{2, 3})
+	var x X
+	if err := x.UnmarshalProto(input); err != nil {
+		t.Fatal(err)
+	}
+	want := X{ID: 5, Nums: []int32{1, 2, 3}}
+	if !reflect.DeepEqual(x, want) {
+		t.Fatalf("wrong result:\n got %+v\nwant %+v", x, want)
+	}
+}
+
+func TestNilListElement(t *testing.T) {
+	// nil elements of repeated messages are encoded as empty messages.
+	enc, err := X{List: []*Y{nil, {S: "a"}}}.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec X
+	if err := dec.UnmarshalProto(enc); err != nil {
+		t.Fatal(err)
+	}
+	want := []*Y{{}, {S: "a"}}
+	if !reflect.DeepEqual(dec.List, want) {
+		t.Fatalf("wrong list %+v, want %+v", dec.List, want)
+	}
+}
+
+func TestDecodingErrors(t *testing.T) {
+	// id is clipped so the appends below don't share memory.
+	id := slices.Clip(protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 5))
+	tests := []struct {
+		input []byte
+		err   string
+	}{
+		{
+			input: nil,
+			err:   "missing required field 'id' for X",
+		},
+		{
+			input: protowire.AppendTag(id, 9, protowire.BytesType),
+			err:   "unexpected EOF",
+		},
+		{
+			input: protowire.AppendBytes(protowire.AppendTag(id, 9, protowire.BytesType), []byte{1}),
+			err:   "field 'hash' has wrong length, need 2 items",
+		},
+		{
+			input: protowire.AppendVarint(protowire.AppendTag(id, 4, protowire.VarintType), 128),
+			err:   "value of field 'small' is out of range for X",
+		},
+		{
+			input: protowire.AppendVarint(protowire.AppendTag(id, 4, protowire.VarintType), 1<<64-129),
+			err:   "value of field 'small' is out of range for X",
+		},
+		{
+			input: protowire.AppendVarint(protowire.AppendTag(id, 10, protowire.VarintType), 1<<31),
+			err:   "value of field 'nums' is out of range for X",
+		},
+		{
+			input: protowire.AppendVarint(protowire.AppendTag(id, 12, protowire.VarintType), 1<<32),
+			err:   "value of field 'opt' is out of range for X",
+		},
+	}
+	for _, test := range tests {
+		var x X
+		err := x.UnmarshalProto(test.input)
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("input %x: wrong error %v, want %q", test.input, err, test.err)
+		}
+	}
+}
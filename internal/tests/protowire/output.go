@@ -0,0 +1,450 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package protowire
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalProto marshals as protobuf.
+func (x X) MarshalProto() ([]byte, error) {
+	type X struct {
+		ID      uint64         `proto:"1" gencodec:"required"`
+		Name    string         `proto:"2"`
+		Flag    bool           `proto:"3"`
+		Small   int8           `proto:"4"`
+		Ratio   float32        `proto:"5"`
+		Score   float64        `proto:"6"`
+		Timeout time.Duration  `proto:"7"`
+		Data    []byte         `proto:"8"`
+		Hash    []replacedUint `proto:"9"`
+		Nums    []int32        `proto:"10"`
+		Names   []string       `proto:"11"`
+		Opt     *uint32        `proto:"12"`
+		Child   Y              `proto:"13"`
+		Next    *Y             `proto:"14"`
+		List    []*Y           `proto:"15"`
+		Skip    int            `proto:"-"`
+	}
+	var enc X
+	enc.ID = x.ID
+	enc.Name = x.Name
+	enc.Flag = x.Flag
+	enc.Small = x.Small
+	enc.Ratio = x.Ratio
+	enc.Score = x.Score
+	enc.Timeout = x.Timeout
+	enc.Data = x.Data
+	enc.Hash = make([]replacedUint, len(x.Hash))
+	for k, v := range x.Hash {
+		enc.Hash[k] = replacedUint(v)
+	}
+	enc.Nums = x.Nums
+	enc.Names = x.Names
+	enc.Opt = x.Opt
+	enc.Child = x.Child
+	enc.Next = x.Next
+	enc.List = x.List
+	enc.Skip = x.Skip
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, enc.ID)
+	if enc.Name != "" {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, enc.Name)
+	}
+	if enc.Flag {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(enc.Flag))
+	}
+	if enc.Small != 0 {
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(enc.Small))
+	}
+	if enc.Ratio != 0 {
+		b = protowire.AppendTag(b, 5, protowire.Fixed32Type)
+		b = protowire.AppendFixed32(b, math.Float32bits(enc.Ratio))
+	}
+	if enc.Score != 0 {
+		b = protowire.AppendTag(b, 6, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(enc.Score))
+	}
+	if enc.Timeout != 0 {
+		b = protowire.AppendTag(b, 7, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(enc.Timeout))
+	}
+	if len(enc.Data) != 0 {
+		b = protowire.AppendTag(b, 8, protowire.BytesType)
+		b = protowire.AppendBytes(b, enc.Data)
+	}
+	if len(enc.Hash) != 0 {
+		var packed []byte
+		for _, elem := range enc.Hash {
+			packed = protowire.AppendVarint(packed, uint64(elem))
+		}
+		b = protowire.AppendTag(b, 9, protowire.BytesType)
+		b = protowire.AppendBytes(b, packed)
+	}
+	if len(enc.Nums) != 0 {
+		var packed0 []byte
+		for _, elem0 := range enc.Nums {
+			packed0 = protowire.AppendVarint(packed0, uint64(elem0))
+		}
+		b = protowire.AppendTag(b, 10, protowire.BytesType)
+		b = protowire.AppendBytes(b, packed0)
+	}
+	for _, elem1 := range enc.Names {
+		b = protowire.AppendTag(b, 11, protowire.BytesType)
+		b = protowire.AppendString(b, elem1)
+	}
+	if enc.Opt != nil {
+		b = protowire.AppendTag(b, 12, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(*enc.Opt))
+	}
+	if mb, err := enc.Child.MarshalProto(); err != nil {
+		return nil, err
+	} else {
+		b = protowire.AppendTag(b, 13, protowire.BytesType)
+		b = protowire.AppendBytes(b, mb)
+	}
+	if enc.Next != nil {
+		if mb0, err := enc.Next.MarshalProto(); err != nil {
+			return nil, err
+		} else {
+			b = protowire.AppendTag(b, 14, protowire.BytesType)
+			b = protowire.AppendBytes(b, mb0)
+		}
+	}
+	for _, elem2 := range enc.List {
+		if elem2 == nil {
+			b = protowire.AppendTag(b, 15, protowire.BytesType)
+			b = protowire.AppendBytes(b, nil)
+			continue
+		}
+		if mb1, err := elem2.MarshalProto(); err != nil {
+			return nil, err
+		} else {
+			b = protowire.AppendTag(b, 15, protowire.BytesType)
+			b = protowire.AppendBytes(b, mb1)
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalProto unmarshals from protobuf.
+func (x *X) UnmarshalProto(input []byte) error {
+	type X struct {
+		ID      *uint64        `proto:"1" gencodec:"required"`
+		Name    *string        `proto:"2"`
+		Flag    *bool          `proto:"3"`
+		Small   *int8          `proto:"4"`
+		Ratio   *float32       `proto:"5"`
+		Score   *float64       `proto:"6"`
+		Timeout *time.Duration `proto:"7"`
+		Data    []byte         `proto:"8"`
+		Hash    []replacedUint `proto:"9"`
+		Nums    []int32        `proto:"10"`
+		Names   []string       `proto:"11"`
+		Opt     *uint32        `proto:"12"`
+		Child   *Y             `proto:"13"`
+		Next    *Y             `proto:"14"`
+		List    []*Y           `proto:"15"`
+		Skip    *int           `proto:"-"`
+	}
+	var dec X
+	for len(input) > 0 {
+		num, typ, n := protowire.ConsumeTag(input)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		input = input[n:]
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			dec.ID = &v
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			dec.Name = &v
+		case num == 3 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			val := protowire.DecodeBool(v)
+			dec.Flag = &val
+		case num == 4 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			if int64(v) < math.MinInt8 || int64(v) > math.MaxInt8 {
+				return errors.New("value of field 'small' is out of range for X")
+			}
+			val := int8(v)
+			dec.Small = &val
+		case num == 5 && typ == protowire.Fixed32Type:
+			v, n := protowire.ConsumeFixed32(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			val := math.Float32frombits(v)
+			dec.Ratio = &val
+		case num == 6 && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			val := math.Float64frombits(v)
+			dec.Score = &val
+		case num == 7 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			val := time.Duration(v)
+			dec.Timeout = &val
+		case num == 8 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			dec.Data = bytes.Clone(v)
+		case num == 9 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			for len(v) > 0 {
+				elem, n := protowire.ConsumeVarint(v)
+				if n < 0 {
+					return protowire.ParseError(n)
+				}
+				v = v[n:]
+				if elem > math.MaxUint {
+					return errors.New("value of field 'hash' is out of range for X")
+				}
+				dec.Hash = append(dec.Hash, replacedUint(elem))
+			}
+		case num == 9 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			if v > math.MaxUint {
+				return errors.New("value of field 'hash' is out of range for X")
+			}
+			dec.Hash = append(dec.Hash, replacedUint(v))
+		case num == 10 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			for len(v) > 0 {
+				elem, n := protowire.ConsumeVarint(v)
+				if n < 0 {
+					return protowire.ParseError(n)
+				}
+				v = v[n:]
+				if int64(elem) < math.MinInt32 || int64(elem) > math.MaxInt32 {
+					return errors.New("value of field 'nums' is out of range for X")
+				}
+				dec.Nums = append(dec.Nums, int32(elem))
+			}
+		case num == 10 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			if int64(v) < math.MinInt32 || int64(v) > math.MaxInt32 {
+				return errors.New("value of field 'nums' is out of range for X")
+			}
+			dec.Nums = append(dec.Nums, int32(v))
+		case num == 11 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			dec.Names = append(dec.Names, v)
+		case num == 12 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			if v > math.MaxUint32 {
+				return errors.New("value of field 'opt' is out of range for X")
+			}
+			val := uint32(v)
+			dec.Opt = &val
+		case num == 13 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			var val Y
+			if err := val.UnmarshalProto(v); err != nil {
+				return err
+			}
+			dec.Child = &val
+		case num == 14 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			var val Y
+			if err := val.UnmarshalProto(v); err != nil {
+				return err
+			}
+			dec.Next = &val
+		case num == 15 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			var val Y
+			if err := val.UnmarshalProto(v); err != nil {
+				return err
+			}
+			dec.List = append(dec.List, &val)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+		}
+	}
+	if dec.ID == nil {
+		return errors.New("missing required field 'id' for X")
+	}
+	x.ID = *dec.ID
+	if dec.Name != nil {
+		x.Name = *dec.Name
+	}
+	if dec.Flag != nil {
+		x.Flag = *dec.Flag
+	}
+	if dec.Small != nil {
+		x.Small = *dec.Small
+	}
+	if dec.Ratio != nil {
+		x.Ratio = *dec.Ratio
+	}
+	if dec.Score != nil {
+		x.Score = *dec.Score
+	}
+	if dec.Timeout != nil {
+		x.Timeout = *dec.Timeout
+	}
+	if dec.Data != nil {
+		x.Data = dec.Data
+	}
+	if dec.Hash != nil {
+		if len(dec.Hash) != len(x.Hash) {
+			return errors.New("field 'hash' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Hash {
+			x.Hash[k] = uint(v)
+		}
+	}
+	if dec.Nums != nil {
+		x.Nums = dec.Nums
+	}
+	if dec.Names != nil {
+		x.Names = dec.Names
+	}
+	if dec.Opt != nil {
+		x.Opt = dec.Opt
+	}
+	if dec.Child != nil {
+		x.Child = *dec.Child
+	}
+	if dec.Next != nil {
+		x.Next = dec.Next
+	}
+	if dec.List != nil {
+		x.List = dec.List
+	}
+	if dec.Skip != nil {
+		x.Skip = *dec.Skip
+	}
+	return nil
+}
+
+// MarshalProto marshals as protobuf.
+func (y Y) MarshalProto() ([]byte, error) {
+	type Y0 struct {
+		S string `proto:"1"`
+	}
+	var enc Y0
+	enc.S = y.S
+	var b []byte
+	if enc.S != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, enc.S)
+	}
+	return b, nil
+}
+
+// UnmarshalProto unmarshals from protobuf.
+func (y *Y) UnmarshalProto(input []byte) error {
+	type Y0 struct {
+		S *string `proto:"1"`
+	}
+	var dec Y0
+	for len(input) > 0 {
+		num, typ, n := protowire.ConsumeTag(input)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		input = input[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			dec.S = &v
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+		}
+	}
+	if dec.S != nil {
+		y.S = *dec.S
+	}
+	return nil
+}
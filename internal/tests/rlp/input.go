@@ -0,0 +1,24 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -field-override Xo -formats rlp -out output.go
+
+package rlp
+
+import "math/big"
+
+type replacedUint uint
+
+type X struct {
+	Num   uint64
+	Arr   [2]uint
+	Big   *big.Int
+	Skip  int     `rlp:"-"`
+	Opt   uint64  `rlp:"optional"`
+	Extra []uint8 `rlp:"optional"`
+}
+
+type Xo struct {
+	Arr []replacedUint
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package rlp
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestRLPRoundTrip(t *testing.T) {
+	x := X{Num: 1, Arr: [2]uint{2, 3}, Big: big.NewInt(4), Opt: 5}
+	enc, err := rlp.EncodeToBytes(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Extra is omitted because it is empty.
+	want, _ := rlp.EncodeToBytes([]any{uint64(1), []uint{2, 3}, big.NewInt(4), uint64(5)})
+	if !bytes.Equal(enc, want) {
+		t.Fatalf("wrong encoding:\n got %x\nwant %x", enc, want)
+	}
+
+	var dec X
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+func TestRLPErrors(t *testing.T) {
+	var x X
+	enc, _ := rlp.EncodeToBytes([]any{uint64(1), []uint{2}, big.NewInt(4)})
+	if err := rlp.DecodeBytes(enc, &x); err == nil {
+		t.Fatal("expected error for wrong array length")
+	}
+	enc, _ = rlp.EncodeToBytes([]any{uint64(1)})
+	if err := rlp.DecodeBytes(enc, &x); err == nil {
+		t.Fatal("expected error for missing fields")
+	}
+}
@@ -0,0 +1,76 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package rlp
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var _ = (*Xo)(nil)
+
+// EncodeRLP marshals as RLP.
+func (x X) EncodeRLP(w io.Writer) error {
+	type X struct {
+		Num   uint64
+		Arr   []replacedUint
+		Big   *big.Int
+		Skip  int     `rlp:"-"`
+		Opt   uint64  `rlp:"optional"`
+		Extra []uint8 `rlp:"optional"`
+	}
+	var enc X
+	enc.Num = x.Num
+	enc.Arr = make([]replacedUint, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedUint(v)
+	}
+	enc.Big = x.Big
+	enc.Skip = x.Skip
+	enc.Opt = x.Opt
+	enc.Extra = x.Extra
+	return rlp.Encode(w, &enc)
+}
+
+// DecodeRLP unmarshals from RLP.
+func (x *X) DecodeRLP(s *rlp.Stream) error {
+	type X struct {
+		Num   *uint64
+		Arr   []replacedUint
+		Big   *big.Int
+		Skip  *int    `rlp:"-"`
+		Opt   *uint64 `rlp:"optional"`
+		Extra []uint8 `rlp:"optional"`
+	}
+	var dec X
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	if dec.Num != nil {
+		x.Num = *dec.Num
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'arr' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = uint(v)
+		}
+	}
+	if dec.Big != nil {
+		x.Big = dec.Big
+	}
+	if dec.Skip != nil {
+		x.Skip = *dec.Skip
+	}
+	if dec.Opt != nil {
+		x.Opt = *dec.Opt
+	}
+	if dec.Extra != nil {
+		x.Extra = dec.Extra
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X:Xo -formats json,json2,yaml,toml,form,protowire,cbor,msgpack,bson -strict -out output.go
+
+package strict
+
+type X struct {
+	Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+	Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+	Plain  bool   `proto:"3"`
+	MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+	Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+}
+
+func (x *X) Double() int {
+	return 2 * x.Count
+}
+
+type Xo struct {
+	Double int `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:build go1.27
+
+package strict
+
+import (
+	"encoding/json"
+	jsonv2 "encoding/json/v2"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"gopkg.in/yaml.v3"
+)
+
+func TestStrictJSON(t *testing.T) {
+	// The output of MarshalJSON includes the key of the Double method.
+	enc, err := json.Marshal(X{Name: "a", Count: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var x X
+	if err := x.UnmarshalJSON(enc); err != nil {
+		t.Fatalf("can't decode %s: %v", enc, err)
+	}
+	// Keys are matched case-insensitively like in package json.
+	if err := x.UnmarshalJSON([]byte(`{"NAME":"a","plain":true}`)); err != nil {
+		t.Fatal(err)
+	}
+	err = x.UnmarshalJSON([]byte(`{"name":"a","cuont":1}`))
+	checkError(t, err, "unknown field 'cuont' for X")
+	err = x.UnmarshalJSON([]byte(`{"name":"a","Ignore":1}`))
+	checkError(t, err, "unknown field 'Ignore' for X")
+}
+
+func TestStrictJSONv2(t *testing.T) {
+	var x X
+	if err := jsonv2.Unmarshal([]byte(`{"name":"a","Plain":true,"double":2}`), &x); err != nil {
+		t.Fatal(err)
+	}
+	// Package json wraps the error.
+	err := jsonv2.Unmarshal([]byte(`{"name":"a","plain":true}`), &x)
+	if err == nil || !strings.HasSuffix(err.Error(), "unknown field 'plain' for X") {
+		t.Fatalf("wrong error %q", err)
+	}
+	// Keys are matched case-insensitively when the option is set.
+	err = jsonv2.Unmarshal([]byte(`{"name":"a","plain":true}`), &x, jsonv2.MatchCaseInsensitiveNames(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrictYAML(t *testing.T) {
+	var x X
+	if err := yaml.Unmarshal([]byte("name: a\ncount: 1\ndouble: 2\n"), &x); err != nil {
+		t.Fatal(err)
+	}
+	err := yaml.Unmarshal([]byte("name: a\nNAME: b\n"), &x)
+	checkError(t, err, "unknown field 'NAME' for X")
+}
+
+func TestStrictForm(t *testing.T) {
+	var x X
+	if err := x.DecodeValues(url.Values{"name": {"a"}, "double": {"2"}}); err != nil {
+		t.Fatal(err)
+	}
+	err := x.DecodeValues(url.Values{"name": {"a"}, "page": {"1"}})
+	checkError(t, err, "unknown field 'page' for X")
+}
+
+func TestStrictProto(t *testing.T) {
+	enc, err := X{Name: "a", Count: 2}.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var x X
+	if err := x.UnmarshalProto(enc); err != nil {
+		t.Fatal(err)
+	}
+	enc = protowire.AppendTag(enc, 9, protowire.VarintType)
+	enc = protowire.AppendVarint(enc, 1)
+	err = x.UnmarshalProto(enc)
+	checkError(t, err, "unknown field 9 for X")
+}
+
+func TestStrictCBOR(t *testing.T) {
+	enc, err := cbor.Marshal(X{Name: "a", Count: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var x X
+	if err := cbor.Unmarshal(enc, &x); err != nil {
+		t.Fatal(err)
+	}
+	// Keys are matched case-insensitively like in package cbor.
+	enc, _ = cbor.Marshal(map[string]any{"NAME": "a", "plain": true})
+	if err := cbor.Unmarshal(enc, &x); err != nil {
+		t.Fatal(err)
+	}
+	enc, _ = cbor.Marshal(map[string]any{"name": "a", "cuont": 1})
+	checkError(t, cbor.Unmarshal(enc, &x), "unknown field 'cuont' for X")
+}
+
+func TestStrictMsgpack(t *testing.T) {
+	enc, err := msgpack.Marshal(X{Name: "a", Count: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var x X
+	if err := msgpack.Unmarshal(enc, &x); err != nil {
+		t.Fatal(err)
+	}
+	// Keys are case-sensitive.
+	enc, _ = msgpack.Marshal(map[string]any{"name": "a", "plain": true})
+	checkError(t, msgpack.Unmarshal(enc, &x), "unknown field 'plain' for X")
+}
+
+func TestStrictBSON(t *testing.T) {
+	enc, err := bson.Marshal(X{Name: "a", Count: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var x X
+	if err := bson.Unmarshal(enc, &x); err != nil {
+		t.Fatal(err)
+	}
+	// Like package bson, keys match if they are equal to the field key in lower case.
+	enc, _ = bson.Marshal(bson.M{"Name": "a", "PLAIN": true, "myKey": "b"})
+	if err := bson.Unmarshal(enc, &x); err != nil {
+		t.Fatal(err)
+	}
+	enc, _ = bson.Marshal(bson.M{"name": "a", "MYKEY": "b"})
+	checkError(t, bson.Unmarshal(enc, &x), "unknown field 'MYKEY' for X")
+	enc, _ = bson.Marshal(bson.M{"name": "a", "ignore": 1})
+	checkError(t, bson.Unmarshal(enc, &x), "unknown field 'ignore' for X")
+}
+
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("no error, want %q", want)
+	}
+	if err.Error() != want {
+		t.Fatalf("wrong error %q\n want %q", err, want)
+	}
+}
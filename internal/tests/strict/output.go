@@ -0,0 +1,693 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package strict
+
+import (
+	"encoding/json"
+	"encoding/json/jsontext"
+	json0 "encoding/json/v2"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input []byte) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(input, &keys); err != nil {
+		return err
+	}
+	for key := range keys {
+		switch strings.ToLower(key) {
+		case "name", "count", "plain", "mykey", "double":
+		default:
+			return fmt.Errorf("unknown field '%s' for X", key)
+		}
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
+
+// MarshalJSONTo marshals as JSON.
+func (x X) MarshalJSONTo(encoder *jsontext.Encoder) error {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	return json0.MarshalEncode(encoder, &enc)
+}
+
+// UnmarshalJSONFrom unmarshals from JSON.
+func (x *X) UnmarshalJSONFrom(decoder *jsontext.Decoder) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	var keys map[string]jsontext.Value
+	var input jsontext.Value
+	if err := json0.UnmarshalDecode(decoder, &input); err != nil {
+		return err
+	}
+	if err := json0.Unmarshal(input, &dec, decoder.Options()); err != nil {
+		return err
+	}
+	if err := json0.Unmarshal(input, &keys, decoder.Options()); err != nil {
+		return err
+	}
+	fold, _ := json0.GetOption(decoder.Options(), json0.MatchCaseInsensitiveNames)
+	for key := range keys {
+		switch key {
+		case "name", "count", "Plain", "myKey", "double":
+		default:
+			if !fold {
+				return fmt.Errorf("unknown field '%s' for X", key)
+			}
+			switch strings.ToLower(key) {
+			case "name", "count", "plain", "mykey", "double":
+			default:
+				return fmt.Errorf("unknown field '%s' for X", key)
+			}
+		}
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
+
+// MarshalYAML marshals as YAML.
+func (x X) MarshalYAML() (interface{}, error) {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (x *X) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	var keys map[string]interface{}
+	if err := unmarshal(&dec); err != nil {
+		return err
+	}
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	for key := range keys {
+		switch key {
+		case "name", "count", "plain", "myKey", "double":
+		default:
+			return fmt.Errorf("unknown field '%s' for X", key)
+		}
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
+
+// MarshalTOML marshals as TOML.
+func (x X) MarshalTOML() (interface{}, error) {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	return &enc, nil
+}
+
+// UnmarshalTOML unmarshals from TOML.
+func (x *X) UnmarshalTOML(unmarshal func(interface{}) error) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	var keys map[string]interface{}
+	if err := unmarshal(&dec); err != nil {
+		return err
+	}
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	for key := range keys {
+		switch strings.ToLower(key) {
+		case "name", "count", "plain", "mykey", "double":
+		default:
+			return fmt.Errorf("unknown field '%s' for X", key)
+		}
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
+
+// EncodeValues marshals as form values.
+func (x X) EncodeValues(values url.Values) error {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	values.Set("name", enc.Name)
+	values.Set("count", strconv.FormatInt(int64(enc.Count), 10))
+	values.Set("plain", strconv.FormatBool(enc.Plain))
+	values.Set("myKey", enc.MyKey)
+	values.Set("double", strconv.FormatInt(int64(enc.Double), 10))
+	return nil
+}
+
+// DecodeValues unmarshals from form values.
+func (x *X) DecodeValues(values url.Values) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	for key := range values {
+		switch key {
+		case "name", "count", "plain", "myKey", "double":
+		default:
+			return fmt.Errorf("unknown field '%s' for X", key)
+		}
+	}
+	if strs := values["name"]; len(strs) != 0 {
+		var val string
+		val = strs[0]
+		dec.Name = &val
+	}
+	if strs := values["count"]; len(strs) != 0 {
+		var val int
+		v, err := strconv.ParseInt(strs[0], 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("invalid value for count: %w", err)
+		}
+		val = int(v)
+		dec.Count = &val
+	}
+	if strs := values["plain"]; len(strs) != 0 {
+		var val bool
+		v0, err := strconv.ParseBool(strs[0])
+		if err != nil {
+			return fmt.Errorf("invalid value for plain: %w", err)
+		}
+		val = v0
+		dec.Plain = &val
+	}
+	if strs := values["myKey"]; len(strs) != 0 {
+		var val string
+		val = strs[0]
+		dec.MyKey = &val
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
+
+// MarshalProto marshals as protobuf.
+func (x X) MarshalProto() ([]byte, error) {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, enc.Name)
+	if enc.Count != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(enc.Count))
+	}
+	if enc.Plain {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(enc.Plain))
+	}
+	if enc.MyKey != "" {
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		b = protowire.AppendString(b, enc.MyKey)
+	}
+	if enc.Double != 0 {
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(enc.Double))
+	}
+	return b, nil
+}
+
+// UnmarshalProto unmarshals from protobuf.
+func (x *X) UnmarshalProto(input []byte) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	for len(input) > 0 {
+		num, typ, n := protowire.ConsumeTag(input)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		input = input[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			dec.Name = &v
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			if int64(v) < math.MinInt || int64(v) > math.MaxInt {
+				return errors.New("value of field 'count' is out of range for X")
+			}
+			val := int(v)
+			dec.Count = &val
+		case num == 3 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			val := protowire.DecodeBool(v)
+			dec.Plain = &val
+		case num == 5 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+			dec.MyKey = &v
+		default:
+			switch num {
+			case 1, 2, 3, 5, 4:
+			default:
+				return fmt.Errorf("unknown field %d for X", num)
+			}
+			n = protowire.ConsumeFieldValue(num, typ, input)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			input = input[n:]
+		}
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
+
+// MarshalCBOR marshals as CBOR.
+func (x X) MarshalCBOR() ([]byte, error) {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	return cbor.Marshal(&enc)
+}
+
+// UnmarshalCBOR unmarshals from CBOR.
+func (x *X) UnmarshalCBOR(input []byte) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	var keys map[string]cbor.RawMessage
+	if err := cbor.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if err := cbor.Unmarshal(input, &keys); err != nil {
+		return err
+	}
+	for key := range keys {
+		switch strings.ToLower(key) {
+		case "name", "count", "plain", "mykey", "double":
+		default:
+			return fmt.Errorf("unknown field '%s' for X", key)
+		}
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
+
+// EncodeMsgpack marshals as MessagePack.
+func (x X) EncodeMsgpack(encoder *msgpack.Encoder) error {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	return encoder.Encode(&enc)
+}
+
+// DecodeMsgpack unmarshals from MessagePack.
+func (x *X) DecodeMsgpack(decoder *msgpack.Decoder) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	var keys map[string]msgpack.RawMessage
+	var input msgpack.RawMessage
+	if err := decoder.Decode(&input); err != nil {
+		return err
+	}
+	if err := msgpack.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if err := msgpack.Unmarshal(input, &keys); err != nil {
+		return err
+	}
+	for key := range keys {
+		switch key {
+		case "name", "count", "Plain", "myKey", "double":
+		default:
+			return fmt.Errorf("unknown field '%s' for X", key)
+		}
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
+
+// MarshalBSON marshals as BSON.
+func (x X) MarshalBSON() ([]byte, error) {
+	type X struct {
+		Name   string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  bool   `proto:"3"`
+		MyKey  string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+		Double int    `json:"double" yaml:"double" toml:"double" form:"double" proto:"4" msgpack:"double" bson:"double"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Plain = x.Plain
+	enc.MyKey = x.MyKey
+	enc.Ignore = x.Ignore
+	enc.Double = x.Double()
+	return bson.Marshal(&enc)
+}
+
+// UnmarshalBSON unmarshals from BSON.
+func (x *X) UnmarshalBSON(input []byte) error {
+	type X struct {
+		Name   *string `json:"name" yaml:"name" toml:"name" form:"name" proto:"1" msgpack:"name" bson:"name" gencodec:"required"`
+		Count  *int    `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitempty" form:"count" proto:"2" msgpack:"count,omitempty" bson:"count,omitempty"`
+		Plain  *bool   `proto:"3"`
+		MyKey  *string `json:"myKey,omitempty" yaml:"myKey,omitempty" toml:"myKey,omitempty" form:"myKey" proto:"5" msgpack:"myKey,omitempty" bson:"myKey,omitempty"`
+		Ignore *int    `json:"-" yaml:"-" toml:"-" form:"-" proto:"-" msgpack:"-" bson:"-"`
+	}
+	var dec X
+	var keys map[string]bson.RawValue
+	if err := bson.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if err := bson.Unmarshal(input, &keys); err != nil {
+		return err
+	}
+	for key := range keys {
+		switch key {
+		case "name", "count", "plain", "myKey", "double":
+		default:
+			switch strings.ToLower(key) {
+			case "name", "count", "plain", "myKey", "double":
+			default:
+				return fmt.Errorf("unknown field '%s' for X", key)
+			}
+		}
+	}
+	if dec.Name == nil {
+		return errors.New("missing required field 'name' for X")
+	}
+	x.Name = *dec.Name
+	if dec.Count != nil {
+		x.Count = *dec.Count
+	}
+	if dec.Plain != nil {
+		x.Plain = *dec.Plain
+	}
+	if dec.MyKey != nil {
+		x.MyKey = *dec.MyKey
+	}
+	if dec.Ignore != nil {
+		x.Ignore = *dec.Ignore
+	}
+	return nil
+}
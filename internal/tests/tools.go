@@ -0,0 +1,12 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:build tools
+
+// Package tests holds the test fixtures of gencodec. It is a separate module so
+// the encoding libraries used by the fixtures don't become dependencies of
+// gencodec itself. The generator is tracked here so 'go generate' can run it.
+package tests
+
+import _ "github.com/fjl/gencodec"
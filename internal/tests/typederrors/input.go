@@ -0,0 +1,16 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -field-override Xo -formats json,yaml -typed-errors -all-errors -out output.go
+
+package typederrors
+
+type X struct {
+	A   int    `gencodec:"required"`
+	Arr [2]int `gencodec:"required"`
+}
+
+type Xo struct {
+	Arr []int
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package typederrors
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fjl/gencodec/codec"
+)
+
+func TestTypedErrorsJSON(t *testing.T) {
+	var x X
+	err := json.Unmarshal([]byte(`{"arr": [1]}`), &x)
+	var ferr *codec.FieldError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("expected *codec.FieldError, got %v", err)
+	}
+	want := codec.FieldError{Type: "X", Field: "a", Format: "json", Reason: codec.ReasonMissing}
+	if *ferr != want {
+		t.Fatalf("wrong error: %+v", ferr)
+	}
+
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2", len(errs))
+	}
+	want = codec.FieldError{Type: "X", Field: "arr", Format: "json", Reason: codec.ReasonWrongLength, Length: 2}
+	if *errs[1].(*codec.FieldError) != want {
+		t.Fatalf("wrong error: %+v", errs[1])
+	}
+	if errs[1].Error() != "field 'arr' has wrong length, need 2 items" {
+		t.Fatalf("wrong error message: %q", errs[1].Error())
+	}
+}
@@ -0,0 +1,92 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package typederrors
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/fjl/gencodec/codec"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	type X struct {
+		A   int   `gencodec:"required"`
+		Arr []int `gencodec:"required"`
+	}
+	var enc X
+	enc.A = x.A
+	enc.Arr = x.Arr[:]
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input []byte) error {
+	type X struct {
+		A   *int  `gencodec:"required"`
+		Arr []int `gencodec:"required"`
+	}
+	var dec X
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	var errs []error
+	if dec.A == nil {
+		errs = append(errs, &codec.FieldError{Type: "X", Field: "a", Format: "json", Reason: codec.ReasonMissing})
+	} else {
+		x.A = *dec.A
+	}
+	if dec.Arr == nil {
+		errs = append(errs, &codec.FieldError{Type: "X", Field: "arr", Format: "json", Reason: codec.ReasonMissing})
+	} else {
+		if len(dec.Arr) != len(x.Arr) {
+			errs = append(errs, &codec.FieldError{Type: "X", Field: "arr", Format: "json", Reason: codec.ReasonWrongLength, Length: 2})
+		} else {
+			copy(x.Arr[:], dec.Arr)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MarshalYAML marshals as YAML.
+func (x X) MarshalYAML() (interface{}, error) {
+	type X struct {
+		A   int   `gencodec:"required"`
+		Arr []int `gencodec:"required"`
+	}
+	var enc X
+	enc.A = x.A
+	enc.Arr = x.Arr[:]
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (x *X) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type X struct {
+		A   *int  `gencodec:"required"`
+		Arr []int `gencodec:"required"`
+	}
+	var dec X
+	if err := unmarshal(&dec); err != nil {
+		return err
+	}
+	var errs []error
+	if dec.A == nil {
+		errs = append(errs, &codec.FieldError{Type: "X", Field: "a", Format: "yaml", Reason: codec.ReasonMissing})
+	} else {
+		x.A = *dec.A
+	}
+	if dec.Arr == nil {
+		errs = append(errs, &codec.FieldError{Type: "X", Field: "arr", Format: "yaml", Reason: codec.ReasonMissing})
+	} else {
+		if len(dec.Arr) != len(x.Arr) {
+			errs = append(errs, &codec.FieldError{Type: "X", Field: "arr", Format: "yaml", Reason: codec.ReasonWrongLength, Length: 2})
+		} else {
+			copy(x.Arr[:], dec.Arr)
+		}
+	}
+	return errors.Join(errs...)
+}
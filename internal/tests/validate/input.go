@@ -0,0 +1,88 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X -formats json -out output.go
+
+package validate
+
+import "time"
+
+type hexString string
+
+const maxRetries = 10
+
+// dec and input have the same names as variables of UnmarshalJSON.
+const (
+	dec   = 1
+	input = 64
+)
+
+type X struct {
+	Port    uint16        `gencodec:"required,min=1"`
+	Ratio   float64       `gencodec:"min=0,max=1"`
+	Retries int           `gencodec:"default=3,max=maxRetries"`
+	Timeout time.Duration `gencodec:"min=1s,max=1h"`
+	Level   string        `gencodec:"oneof=debug|info|warn"`
+	Mode    int           `gencodec:"oneof=1|2|4"`
+	Hash    hexString     `gencodec:"pattern=^0x[0-9a-f]{2,8}$"`
+	Name    string        `gencodec:"pattern=^[a-z]+$,len=4"`
+	Key     []byte        `gencodec:"len=4"`
+	Workers int           `gencodec:"min=dec,max=input"`
+}
+
+// Types with invalid constraints, used by TestConstraintErrors.
+
+type badMinString struct {
+	V string `gencodec:"min=1"`
+}
+
+type badPatternInt struct {
+	V int `gencodec:"pattern=[0-9]"`
+}
+
+type badPattern struct {
+	V string `gencodec:"pattern=[0-9"`
+}
+
+type badLenArray struct {
+	V [4]byte `gencodec:"len=4"`
+}
+
+type badOneofValue struct {
+	V uint8 `gencodec:"oneof=1|256"`
+}
+
+type badOneofDuplicate struct {
+	V string `gencodec:"oneof=a|b|a"`
+}
+
+type badOneofEqual struct {
+	V int `gencodec:"oneof=maxRetries|10"`
+}
+
+type badMinMax struct {
+	V int `gencodec:"min=5,max=1"`
+}
+
+type badDefaultMin struct {
+	V int `gencodec:"default=0,min=1"`
+}
+
+type badDefaultOneof struct {
+	V string `gencodec:"default=trace,oneof=debug|info"`
+}
+
+type badDefaultPattern struct {
+	V string `gencodec:"default=ABC,pattern=^[a-z]+$"`
+}
+
+// A and AB have pattern variables with the same default name, used by TestPatternNames.
+
+type A struct {
+	BName string `gencodec:"pattern=^a$"`
+}
+
+type AB struct {
+	Name string `gencodec:"pattern=^b$"`
+}
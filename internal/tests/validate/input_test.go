@@ -0,0 +1,60 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestValidInput(t *testing.T) {
+	input := `{"Port": 80, "Ratio": 0.5, "Timeout": 1000000000, "Level": "info", "Mode": 4, "Hash": "0xab", "Name": "abcd", "Key": "AQIDBA=="}`
+	var x X
+	if err := json.Unmarshal([]byte(input), &x); err != nil {
+		t.Fatal(err)
+	}
+	if x.Timeout != time.Second || x.Retries != 3 {
+		t.Errorf("wrong result: %+v", x)
+	}
+}
+
+func TestConstraintErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{`{"Port": 0}`, "field 'port' violates constraint min=1"},
+		{`{"Port": 1, "Ratio": 1.5}`, "field 'ratio' violates constraint max=1"},
+		{`{"Port": 1, "Ratio": -1}`, "field 'ratio' violates constraint min=0"},
+		{`{"Port": 1, "Retries": 11}`, "field 'retries' violates constraint max=maxRetries"},
+		{`{"Port": 1, "Timeout": 1000}`, "field 'timeout' violates constraint min=1s"},
+		{`{"Port": 1, "Level": "error"}`, "field 'level' violates constraint oneof=debug|info|warn"},
+		{`{"Port": 1, "Mode": 3}`, "field 'mode' violates constraint oneof=1|2|4"},
+		{`{"Port": 1, "Hash": "0xABCD"}`, "field 'hash' violates constraint pattern=^0x[0-9a-f]{2,8}$"},
+		{`{"Port": 1, "Name": "abc"}`, "field 'name' violates constraint len=4"},
+		{`{"Port": 1, "Name": "ab12"}`, "field 'name' violates constraint pattern=^[a-z]+$"},
+		{`{"Port": 1, "Key": "AQI="}`, "field 'key' violates constraint len=4"},
+		{`{"Port": 1, "Workers": 0}`, "field 'workers' violates constraint min=dec"},
+		{`{"Port": 1, "Workers": 65}`, "field 'workers' violates constraint max=input"},
+	}
+	for _, test := range tests {
+		var x X
+		err := json.Unmarshal([]byte(test.input), &x)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("input %s: wrong error %q, want %q", test.input, err, test.err)
+		}
+	}
+}
+
+func TestConstraintErrorKeepsValue(t *testing.T) {
+	x := X{Port: 80}
+	if err := json.Unmarshal([]byte(`{"Port": 0}`), &x); err == nil {
+		t.Fatal("no error for invalid input")
+	}
+	if x.Port != 80 {
+		t.Errorf("rejected value was assigned: Port = %d", x.Port)
+	}
+}
@@ -0,0 +1,141 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
+)
+
+var xHashPattern = regexp.MustCompile(`^0x[0-9a-f]{2,8}$`)
+var xNamePattern = regexp.MustCompile(`^[a-z]+$`)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	type X struct {
+		Port    uint16        `gencodec:"required,min=1"`
+		Ratio   float64       `gencodec:"min=0,max=1"`
+		Retries int           `gencodec:"default=3,max=maxRetries"`
+		Timeout time.Duration `gencodec:"min=1s,max=1h"`
+		Level   string        `gencodec:"oneof=debug|info|warn"`
+		Mode    int           `gencodec:"oneof=1|2|4"`
+		Hash    hexString     `gencodec:"pattern=^0x[0-9a-f]{2,8}$"`
+		Name    string        `gencodec:"pattern=^[a-z]+$,len=4"`
+		Key     []byte        `gencodec:"len=4"`
+		Workers int           `gencodec:"min=dec,max=input"`
+	}
+	var enc X
+	enc.Port = x.Port
+	enc.Ratio = x.Ratio
+	enc.Retries = x.Retries
+	enc.Timeout = x.Timeout
+	enc.Level = x.Level
+	enc.Mode = x.Mode
+	enc.Hash = x.Hash
+	enc.Name = x.Name
+	enc.Key = x.Key
+	enc.Workers = x.Workers
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input0 []byte) error {
+	type X struct {
+		Port    *uint16        `gencodec:"required,min=1"`
+		Ratio   *float64       `gencodec:"min=0,max=1"`
+		Retries *int           `gencodec:"default=3,max=maxRetries"`
+		Timeout *time.Duration `gencodec:"min=1s,max=1h"`
+		Level   *string        `gencodec:"oneof=debug|info|warn"`
+		Mode    *int           `gencodec:"oneof=1|2|4"`
+		Hash    *hexString     `gencodec:"pattern=^0x[0-9a-f]{2,8}$"`
+		Name    *string        `gencodec:"pattern=^[a-z]+$,len=4"`
+		Key     []byte         `gencodec:"len=4"`
+		Workers *int           `gencodec:"min=dec,max=input"`
+	}
+	var dec0 X
+	if err := json.Unmarshal(input0, &dec0); err != nil {
+		return err
+	}
+	if dec0.Port == nil {
+		return errors.New("missing required field 'port' for X")
+	}
+	if *dec0.Port < 1 {
+		return errors.New("field 'port' violates constraint min=1")
+	}
+	x.Port = *dec0.Port
+	if dec0.Ratio != nil {
+		if *dec0.Ratio < 0 {
+			return errors.New("field 'ratio' violates constraint min=0")
+		}
+		if *dec0.Ratio > 1 {
+			return errors.New("field 'ratio' violates constraint max=1")
+		}
+		x.Ratio = *dec0.Ratio
+	}
+	if dec0.Retries != nil {
+		if *dec0.Retries > maxRetries {
+			return errors.New("field 'retries' violates constraint max=maxRetries")
+		}
+		x.Retries = *dec0.Retries
+	} else {
+		x.Retries = 3
+	}
+	if dec0.Timeout != nil {
+		if *dec0.Timeout < 1*time.Second {
+			return errors.New("field 'timeout' violates constraint min=1s")
+		}
+		if *dec0.Timeout > 1*time.Hour {
+			return errors.New("field 'timeout' violates constraint max=1h")
+		}
+		x.Timeout = *dec0.Timeout
+	}
+	if dec0.Level != nil {
+		switch *dec0.Level {
+		case "debug", "info", "warn":
+		default:
+			return errors.New("field 'level' violates constraint oneof=debug|info|warn")
+		}
+		x.Level = *dec0.Level
+	}
+	if dec0.Mode != nil {
+		switch *dec0.Mode {
+		case 1, 2, 4:
+		default:
+			return errors.New("field 'mode' violates constraint oneof=1|2|4")
+		}
+		x.Mode = *dec0.Mode
+	}
+	if dec0.Hash != nil {
+		if !xHashPattern.MatchString(string(*dec0.Hash)) {
+			return errors.New("field 'hash' violates constraint pattern=^0x[0-9a-f]{2,8}$")
+		}
+		x.Hash = *dec0.Hash
+	}
+	if dec0.Name != nil {
+		if !xNamePattern.MatchString(*dec0.Name) {
+			return errors.New("field 'name' violates constraint pattern=^[a-z]+$")
+		}
+		if len(*dec0.Name) != 4 {
+			return errors.New("field 'name' violates constraint len=4")
+		}
+		x.Name = *dec0.Name
+	}
+	if dec0.Key != nil {
+		if len(dec0.Key) != 4 {
+			return errors.New("field 'key' violates constraint len=4")
+		}
+		x.Key = dec0.Key
+	}
+	if dec0.Workers != nil {
+		if *dec0.Workers < dec {
+			return errors.New("field 'workers' violates constraint min=dec")
+		}
+		if *dec0.Workers > input {
+			return errors.New("field 'workers' violates constraint max=input")
+		}
+		x.Workers = *dec0.Workers
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X:Xo -formats json -all-errors -validate -out output.go
+
+package validatemethod
+
+import "math/big"
+
+type X struct {
+	Name    string   `gencodec:"required,pattern=^[a-z]+$"`
+	Count   int      `gencodec:"required,min=1"`
+	Level   string   `gencodec:"oneof=debug|info" json:"logLevel"`
+	Items   []int    `gencodec:"len=2"`
+	Pair    []string `gencodec:"required"`
+	Enabled bool     `gencodec:"required"`
+	Num     *big.Int `gencodec:"required"`
+	Inner   Inner    `gencodec:"required"`
+	Skipped int      `gencodec:"required" json:"-"`
+}
+
+type Inner struct {
+	A, B int
+}
+
+type Xo struct {
+	Pair [2]string
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package validatemethod
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	x := X{
+		Name:    "abc",
+		Count:   1,
+		Pair:    []string{"a", "b"},
+		Enabled: true,
+		Num:     big.NewInt(1),
+		Inner:   Inner{A: 1},
+	}
+	if err := x.Validate(); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	x = X{Name: "ABC", Count: -1, Level: "warn", Items: []int{1}, Pair: []string{"a"}}
+	// Fields are named by their JSON key, like in the errors of UnmarshalJSON.
+	want := `field 'name' violates constraint pattern=^[a-z]+$
+field 'count' violates constraint min=1
+field 'logLevel' violates constraint oneof=debug|info
+field 'items' violates constraint len=2
+field 'pair' has wrong length, need 2 items
+missing required field 'enabled' for X
+missing required field 'num' for X
+missing required field 'inner' for X`
+	if err := x.Validate(); err == nil || err.Error() != want {
+		t.Fatalf("wrong error:\n%v", err)
+	}
+}
+
+func TestValidateMatchesUnmarshal(t *testing.T) {
+	// Validate and UnmarshalJSON report the same field name.
+	const want = "field 'logLevel' violates constraint oneof=debug|info"
+	var x X
+	err := x.UnmarshalJSON([]byte(`{"logLevel": "warn"}`))
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("UnmarshalJSON: wrong error %v", err)
+	}
+	x = X{Name: "abc", Count: 1, Level: "warn", Pair: []string{"a", "b"}, Enabled: true, Num: big.NewInt(1), Inner: Inner{A: 1}}
+	if err := x.Validate(); err == nil || err.Error() != want {
+		t.Fatalf("Validate: wrong error %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package validatemethod
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"regexp"
+)
+
+var _ = (*Xo)(nil)
+var xNamePattern = regexp.MustCompile(`^[a-z]+$`)
+
+// MarshalJSON marshals as JSON.
+func (x X) MarshalJSON() ([]byte, error) {
+	type X struct {
+		Name    string    `gencodec:"required,pattern=^[a-z]+$"`
+		Count   int       `gencodec:"required,min=1"`
+		Level   string    `gencodec:"oneof=debug|info" json:"logLevel"`
+		Items   []int     `gencodec:"len=2"`
+		Pair    [2]string `gencodec:"required"`
+		Enabled bool      `gencodec:"required"`
+		Num     *big.Int  `gencodec:"required"`
+		Inner   Inner     `gencodec:"required"`
+		Skipped int       `gencodec:"required" json:"-"`
+	}
+	var enc X
+	enc.Name = x.Name
+	enc.Count = x.Count
+	enc.Level = x.Level
+	enc.Items = x.Items
+	copy(enc.Pair[:], x.Pair)
+	enc.Enabled = x.Enabled
+	enc.Num = x.Num
+	enc.Inner = x.Inner
+	enc.Skipped = x.Skipped
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (x *X) UnmarshalJSON(input []byte) error {
+	type X struct {
+		Name    *string    `gencodec:"required,pattern=^[a-z]+$"`
+		Count   *int       `gencodec:"required,min=1"`
+		Level   *string    `gencodec:"oneof=debug|info" json:"logLevel"`
+		Items   []int      `gencodec:"len=2"`
+		Pair    *[2]string `gencodec:"required"`
+		Enabled *bool      `gencodec:"required"`
+		Num     *big.Int   `gencodec:"required"`
+		Inner   *Inner     `gencodec:"required"`
+		Skipped *int       `gencodec:"required" json:"-"`
+	}
+	var dec X
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	var errs []error
+	if dec.Name == nil {
+		errs = append(errs, errors.New("missing required field 'name' for X"))
+	} else {
+		if !xNamePattern.MatchString(*dec.Name) {
+			errs = append(errs, errors.New("field 'name' violates constraint pattern=^[a-z]+$"))
+		} else {
+			x.Name = *dec.Name
+		}
+	}
+	if dec.Count == nil {
+		errs = append(errs, errors.New("missing required field 'count' for X"))
+	} else {
+		if *dec.Count < 1 {
+			errs = append(errs, errors.New("field 'count' violates constraint min=1"))
+		} else {
+			x.Count = *dec.Count
+		}
+	}
+	if dec.Level != nil {
+		switch *dec.Level {
+		case "debug", "info":
+			x.Level = *dec.Level
+		default:
+			errs = append(errs, errors.New("field 'logLevel' violates constraint oneof=debug|info"))
+		}
+	}
+	if dec.Items != nil {
+		if len(dec.Items) != 2 {
+			errs = append(errs, errors.New("field 'items' violates constraint len=2"))
+		} else {
+			x.Items = dec.Items
+		}
+	}
+	if dec.Pair == nil {
+		errs = append(errs, errors.New("missing required field 'pair' for X"))
+	} else {
+		x.Pair = (*dec.Pair)[:]
+	}
+	if dec.Enabled == nil {
+		errs = append(errs, errors.New("missing required field 'enabled' for X"))
+	} else {
+		x.Enabled = *dec.Enabled
+	}
+	if dec.Num == nil {
+		errs = append(errs, errors.New("missing required field 'num' for X"))
+	} else {
+		x.Num = dec.Num
+	}
+	if dec.Inner == nil {
+		errs = append(errs, errors.New("missing required field 'inner' for X"))
+	} else {
+		x.Inner = *dec.Inner
+	}
+	if dec.Skipped != nil {
+		x.Skipped = *dec.Skipped
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks that required fields are set and all fields satisfy their constraints.
+func (x X) Validate() error {
+	var errs []error
+	if x.Name == "" {
+		errs = append(errs, errors.New("missing required field 'name' for X"))
+	} else {
+		if !xNamePattern.MatchString(x.Name) {
+			errs = append(errs, errors.New("field 'name' violates constraint pattern=^[a-z]+$"))
+		}
+	}
+	if x.Count == 0 {
+		errs = append(errs, errors.New("missing required field 'count' for X"))
+	} else {
+		if x.Count < 1 {
+			errs = append(errs, errors.New("field 'count' violates constraint min=1"))
+		}
+	}
+	if x.Level != "" {
+		switch x.Level {
+		case "debug", "info":
+		default:
+			errs = append(errs, errors.New("field 'logLevel' violates constraint oneof=debug|info"))
+		}
+	}
+	if x.Items != nil {
+		if len(x.Items) != 2 {
+			errs = append(errs, errors.New("field 'items' violates constraint len=2"))
+		}
+	}
+	if x.Pair == nil {
+		errs = append(errs, errors.New("missing required field 'pair' for X"))
+	} else {
+		if len(x.Pair) != 2 {
+			errs = append(errs, errors.New("field 'pair' has wrong length, need 2 items"))
+		}
+	}
+	if !x.Enabled {
+		errs = append(errs, errors.New("missing required field 'enabled' for X"))
+	}
+	if x.Num == nil {
+		errs = append(errs, errors.New("missing required field 'num' for X"))
+	}
+	if x.Inner == (Inner{}) {
+		errs = append(errs, errors.New("missing required field 'inner' for X"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,27 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X:Xo,Y -formats xml -out output.go
+
+package xml
+
+import "encoding/xml"
+
+type replacedInt int
+
+type X struct {
+	XMLName xml.Name `xml:"x"`
+	ID      int      `xml:"id,attr" gencodec:"required"`
+	Arr     [2]int   `xml:"list>item"`
+	Text    string   `xml:",chardata"`
+	Comment string   `xml:",comment"`
+}
+
+type Y struct {
+	Inner string `xml:",innerxml" gencodec:"required"`
+}
+
+type Xo struct {
+	Arr []replacedInt
+}
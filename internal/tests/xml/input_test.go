@@ -0,0 +1,55 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package xml
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestXMLRoundTrip(t *testing.T) {
+	x := X{ID: 1, Arr: [2]int{2, 3}, Text: "text", Comment: "c"}
+	enc, err := xml.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<x id="1"><list><item>2</item><item>3</item></list>text<!--c--></x>`
+	if string(enc) != want {
+		t.Fatalf("wrong encoding:\n got %s\nwant %s", enc, want)
+	}
+
+	var dec X
+	if err := xml.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	x.XMLName = xml.Name{Local: "x"}
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+func TestXMLErrors(t *testing.T) {
+	var x X
+	if err := xml.Unmarshal([]byte(`<x></x>`), &x); err == nil {
+		t.Fatal("expected error for missing required attribute")
+	}
+	if err := xml.Unmarshal([]byte(`<x id="1"><list><item>1</item></list></x>`), &x); err == nil {
+		t.Fatal("expected error for wrong array length")
+	}
+}
+
+func TestXMLInner(t *testing.T) {
+	var y Y
+	if err := xml.Unmarshal([]byte(`<y><a>1</a></y>`), &y); err != nil {
+		t.Fatal(err)
+	}
+	if y.Inner != "<a>1</a>" {
+		t.Fatalf("wrong inner XML %q", y.Inner)
+	}
+	if err := xml.Unmarshal([]byte(`<y></y>`), &y); err == nil {
+		t.Fatal("expected error for missing inner XML")
+	}
+}
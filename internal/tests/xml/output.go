@@ -0,0 +1,93 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package xml
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalXML marshals as XML.
+func (x X) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	type X struct {
+		XMLName xml.Name      `xml:"x"`
+		ID      int           `xml:"id,attr" gencodec:"required"`
+		Arr     []replacedInt `xml:"list>item"`
+		Text    string        `xml:",chardata"`
+		Comment string        `xml:",comment"`
+	}
+	var enc X
+	enc.XMLName = x.XMLName
+	enc.ID = x.ID
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	enc.Text = x.Text
+	enc.Comment = x.Comment
+	start.Name = xml.Name{Local: "x"}
+	return encoder.EncodeElement(&enc, start)
+}
+
+// UnmarshalXML unmarshals from XML.
+func (x *X) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	type X struct {
+		XMLName xml.Name      `xml:"x"`
+		ID      *int          `xml:"id,attr" gencodec:"required"`
+		Arr     []replacedInt `xml:"list>item"`
+		Text    *string       `xml:",chardata"`
+		Comment string        `xml:",comment"`
+	}
+	var dec X
+	if err := decoder.DecodeElement(&dec, &start); err != nil {
+		return err
+	}
+	x.XMLName = dec.XMLName
+	if dec.ID == nil {
+		return errors.New("missing required field 'id' for X")
+	}
+	x.ID = *dec.ID
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return errors.New("field 'item' has wrong length, need 2 items")
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = int(v)
+		}
+	}
+	if dec.Text != nil {
+		x.Text = *dec.Text
+	}
+	if dec.Comment != "" {
+		x.Comment = dec.Comment
+	}
+	return nil
+}
+
+// MarshalXML marshals as XML.
+func (y Y) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	type Y struct {
+		Inner string `xml:",innerxml" gencodec:"required"`
+	}
+	var enc Y
+	enc.Inner = y.Inner
+	return encoder.EncodeElement(&enc, start)
+}
+
+// UnmarshalXML unmarshals from XML.
+func (y *Y) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	type Y struct {
+		Inner string `xml:",innerxml" gencodec:"required"`
+	}
+	var dec Y
+	if err := decoder.DecodeElement(&dec, &start); err != nil {
+		return err
+	}
+	if dec.Inner == "" {
+		return errors.New("missing required field 'inner' for Y")
+	}
+	y.Inner = dec.Inner
+	return nil
+}
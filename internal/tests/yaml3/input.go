@@ -0,0 +1,24 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+//go:generate go run github.com/fjl/gencodec -type X:Xo,Y -formats yaml3 -out output.go
+
+package yaml3
+
+type replacedInt int
+
+type X struct {
+	Int  int    `yaml:"int" gencodec:"required"`
+	Name string `yaml:"name,omitempty"`
+	Arr  [2]int `yaml:"arr"`
+	Sub  *Y     `yaml:"sub"`
+}
+
+type Y struct {
+	A string `yaml:"a" gencodec:"required"`
+}
+
+type Xo struct {
+	Arr []replacedInt
+}
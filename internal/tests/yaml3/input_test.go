@@ -0,0 +1,54 @@
+// Copyright 2025 Felix Lange <fjl@twurst.com>.
+// Use of this source code is governed by the MIT license,
+// which can be found in the LICENSE file.
+
+package yaml3
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAML3RoundTrip(t *testing.T) {
+	x := X{Int: 1, Arr: [2]int{2, 3}, Sub: &Y{A: "a"}}
+	enc, err := yaml.Marshal(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec X
+	if err := yaml.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, x) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", dec, x)
+	}
+}
+
+func TestYAML3ErrorPosition(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{
+			input: "name: x\n",
+			err:   "line 1, column 1: missing required field 'int' for X",
+		},
+		{
+			input: "int: 1\nsub:\n  b: 1\n",
+			err:   "line 3, column 3: missing required field 'a' for Y",
+		},
+		{
+			input: "int: 1\narr: [1]\n",
+			err:   "line 1, column 1: field 'arr' has wrong length, need 2 items",
+		},
+	}
+	for _, test := range tests {
+		var x X
+		err := yaml.Unmarshal([]byte(test.input), &x)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("input %q: wrong error %v, want %q", test.input, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Code generated by github.com/fjl/gencodec. DO NOT EDIT.
+
+package yaml3
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+var _ = (*Xo)(nil)
+
+// MarshalYAML marshals as YAML.
+func (x X) MarshalYAML() (interface{}, error) {
+	type X struct {
+		Int  int           `yaml:"int" gencodec:"required"`
+		Name string        `yaml:"name,omitempty"`
+		Arr  []replacedInt `yaml:"arr"`
+		Sub  *Y            `yaml:"sub"`
+	}
+	var enc X
+	enc.Int = x.Int
+	enc.Name = x.Name
+	enc.Arr = make([]replacedInt, len(x.Arr))
+	for k, v := range x.Arr {
+		enc.Arr[k] = replacedInt(v)
+	}
+	enc.Sub = x.Sub
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (x *X) UnmarshalYAML(node *yaml.Node) error {
+	type X struct {
+		Int  *int          `yaml:"int" gencodec:"required"`
+		Name *string       `yaml:"name,omitempty"`
+		Arr  []replacedInt `yaml:"arr"`
+		Sub  *Y            `yaml:"sub"`
+	}
+	var dec X
+	if err := node.Decode(&dec); err != nil {
+		return err
+	}
+	if dec.Int == nil {
+		return fmt.Errorf("line %d, column %d: missing required field 'int' for X", node.Line, node.Column)
+	}
+	x.Int = *dec.Int
+	if dec.Name != nil {
+		x.Name = *dec.Name
+	}
+	if dec.Arr != nil {
+		if len(dec.Arr) != len(x.Arr) {
+			return fmt.Errorf("line %d, column %d: field 'arr' has wrong length, need 2 items", node.Line, node.Column)
+		}
+		for k, v := range dec.Arr {
+			x.Arr[k] = int(v)
+		}
+	}
+	if dec.Sub != nil {
+		x.Sub = dec.Sub
+	}
+	return nil
+}
+
+// MarshalYAML marshals as YAML.
+func (y Y) MarshalYAML() (interface{}, error) {
+	type Y0 struct {
+		A string `yaml:"a" gencodec:"required"`
+	}
+	var enc Y0
+	enc.A = y.A
+	return &enc, nil
+}
+
+// UnmarshalYAML unmarshals from YAML.
+func (y *Y) UnmarshalYAML(node *yaml.Node) error {
+	type Y0 struct {
+		A *string `yaml:"a" gencodec:"required"`
+	}
+	var dec Y0
+	if err := node.Decode(&dec); err != nil {
+		return err
+	}
+	if dec.A == nil {
+		return fmt.Errorf("line %d, column %d: missing required field 'a' for Y", node.Line, node.Column)
+	}
+	y.A = *dec.A
+	return nil
+}
@@ -11,11 +11,187 @@ features which the standard json package cannot offer.
 
 	gencodec -type MyType -formats json,yaml,toml -out mytype_json.go
 
+Methods for several types can be generated into a single file by giving a comma-separated
+list of types. Each type name can be followed by a colon and the name of its field
+override type (see below).
+
+	gencodec -type MyType:myTypeMarshaling,OtherType -out types_json.go
+
+Types declared in _test.go files of the package can be selected as well.
+
+# Formats
+
+The -formats flag selects the methods that are generated:
+
+  - json: MarshalJSON and UnmarshalJSON for package encoding/json
+  - json2: MarshalJSONTo and UnmarshalJSONFrom for package encoding/json/v2 (Go 1.27 or later)
+  - cbor: MarshalCBOR and UnmarshalCBOR for package github.com/fxamacker/cbor/v2
+  - msgpack: EncodeMsgpack and DecodeMsgpack for package github.com/vmihailenco/msgpack/v5
+  - rlp: EncodeRLP and DecodeRLP for package github.com/ethereum/go-ethereum/rlp
+  - bson: MarshalBSON and UnmarshalBSON for package go.mongodb.org/mongo-driver/v2/bson
+  - yaml: MarshalYAML and UnmarshalYAML
+  - yaml3: MarshalYAML and UnmarshalYAML for package gopkg.in/yaml.v3
+  - toml: MarshalTOML and UnmarshalTOML
+  - xml: MarshalXML and UnmarshalXML for package encoding/xml
+  - env: UnmarshalEnv, which reads environment variables
+  - form: EncodeValues and DecodeValues for url.Values of package net/url
+  - protowire: MarshalProto and UnmarshalProto, which use the protobuf wire format
+
+The json2 format uses the "json" struct tag, and yaml3 uses the "yaml" tag. All other
+formats use the struct tag named like the format, e.g. "cbor". Note that package cbor
+also reads "json" tags of fields without a "cbor" tag.
+
+The UnmarshalYAML method generated for yaml3 decodes from a *yaml.Node. Errors for
+missing and invalid fields contain the line and column of the node.
+
+The rlp format encodes fields as a list, in the order of their declaration. The "rlp"
+struct tag holds options of package rlp instead of a field name. Trailing fields can be
+declared optional using the rlp:"optional" tag. If a field is optional, all fields after
+it must be optional as well.
+
+The xml format supports the options of package encoding/xml, such as ",attr" and
+",chardata". Fields with the ",innerxml" or ",comment" option are only assigned when
+they are non-empty in the input. If the struct has an XMLName field, MarshalXML uses it
+to determine the element name like package xml does. Like package xml, the xml format
+doesn't support map fields unless the map type has a MarshalXML method.
+
+The env format reads each field from the environment variable named by its "env" tag.
+Without a tag, the variable name is the field name in upper snake case, e.g. field
+HTTPPort is read from HTTP_PORT. Fields can have string, boolean, numeric and
+time.Duration types, or a type implementing encoding.TextUnmarshaler. Slices of these
+types are read from comma-separated lists. UnmarshalEnv takes a lookup function like
+os.LookupEnv.
+
+The form format stores fields as URL query parameters named by the "form" tag. It
+supports the same field types as env. Slices are stored as repeated parameters, and
+DecodeValues uses the first value of other fields. The ",omitempty" option skips empty
+fields in EncodeValues.
+
+The protowire format encodes fields using package
+google.golang.org/protobuf/encoding/protowire. Each field needs a field number, which
+is assigned by the "proto" struct tag, e.g. proto:"1". Fields can have boolean,
+numeric, string and []byte types. Pointers to these types are optional fields, and
+slices are repeated fields. Fields of struct type are encoded as embedded messages and
+must have MarshalProto and UnmarshalProto methods. As in proto3, zero values are not
+encoded unless the field is required. nil elements of repeated message fields are
+encoded as empty messages. Decoding a number which doesn't fit into the integer type
+of its field is an error. Use -proto-out to write a .proto file declaring the messages.
+
+# Directives
+
+Instead of listing types on the command line, types can be annotated with a
+//gencodec:generate comment. When gencodec is invoked without -type, it processes
+all annotated types in the package directory. Use "-dir ./..." to include all
+packages below the current directory.
+
+	//gencodec:generate formats=json,yaml override=fooMarshaling out=foo_json.go
+	type foo struct {
+		...
+	}
+
+The directive accepts these options:
+
+  - formats: the marshaling formats to generate (default is the value of -formats)
+  - override: the field override type (see below)
+  - out: the output file name, relative to the package directory (default gencodec.go)
+
+Types with the same output file are written to a single file.
+
+# Checking Generated Files
+
+When invoked with -check, gencodec does not write any files. Instead, it compares the
+generated code with the existing output files and prints a diff if they are not up to
+date. The exit status is non-zero in that case, which makes -check useful in CI.
+
+	gencodec -type MyType -out mytype_json.go -check
+
+# Library
+
+The generator is also available as package github.com/fjl/gencodec/gen. Programs can
+call gen.Generate to create code without running the gencodec command, and add their own
+formats using gen.RegisterFormat. Problems in the input package, such as type errors,
+are returned as diagnostics. The gencodec command prints them as warnings.
+
 # Struct Tags
 
 The gencodec:"required" tag can be used to generate a presence check for the field.
 The generated unmarshaling method returns an error if a required field is missing.
 
+By default, the generated unmarshaling method stops at the first missing field. When
+gencodec is invoked with -all-errors, all missing required fields and arrays of wrong
+length are reported at once, combined using errors.Join. This requires Go 1.20 or later.
+
+The default option assigns a value to optional fields which are absent in the input.
+For fields of string type, the default is the text following "default=". For other
+basic types, it must be a constant expression assignable to the field, which can refer
+to constants of the package. Fields of type time.Duration also accept duration strings
+like "1m30s". Defaults are checked when generating code. Options in the gencodec tag
+are separated by commas, but the default value may contain commas.
+
+	type config struct {
+		Host    string        `gencodec:"default=localhost"`
+		Port    uint16        `gencodec:"default=DefaultPort"`
+		Timeout time.Duration `gencodec:"default=30s"`
+	}
+
+Constraint options are checked by the generated unmarshaling methods after a field has
+been decoded. A value which violates them is not assigned to the field. They are
+verified against the field type when generating code, and the default value of the
+field must satisfy them.
+
+  - min=N, max=N: bounds for numeric fields, given like default values
+  - oneof=a|b|c: the allowed values of a string or integer field, which must be distinct
+  - pattern=RE: a regular expression which string fields must match
+  - len=N: the exact length of a string, slice or map field
+
+For example:
+
+	type config struct {
+		Port  uint16 `gencodec:"required,min=1"`
+		Level string `gencodec:"oneof=debug|info|warn"`
+		Hash  string `gencodec:"pattern=^0x[0-9a-f]+$,len=66"`
+	}
+
+Like the default value, patterns may contain commas.
+
+The alias option gives a field an additional name which is accepted by the unmarshaling
+methods. This is useful when a field is renamed but old input must still be decoded. The
+marshaling methods only write the current name. It is an error if the input contains
+both names. An alias must differ from the names of all fields and from other aliases.
+Aliases can't be used with the rlp and protowire formats, which don't identify fields
+by name.
+
+	type config struct {
+		Timeout int `json:"timeout" gencodec:"alias=timeoutSeconds"`
+	}
+
+When gencodec is invoked with -validate, it also generates a Validate method. Values
+constructed in Go code don't pass through the unmarshaling methods, and Validate can be
+used to check them. It reports required fields holding the zero value, slices which are
+encoded as arrays of a different length, and fields violating their constraints.
+Constraints of optional fields are only checked if the field is not zero. Errors refer
+to fields by their encoded name in the first format listed in -formats, like the errors
+of that format's unmarshaling method.
+
+With -strict, the generated unmarshaling methods return an error when the input
+contains a key which doesn't belong to any field, such as a misspelled option in a
+configuration file. The error names the unknown key. Keys of fields which are replaced by
+methods in the field override are accepted, so the output of the marshaling methods can
+always be decoded. Strict mode is supported by the json, json2, yaml, yaml3, toml, form,
+cbor, msgpack, bson and protowire formats. For protowire, unknown field numbers are
+rejected. The rlp format is always strict, and env ignores the option because the
+environment contains unrelated variables. The xml format and custom formats report an
+error when used with -strict.
+
+To find the keys of the input, the json, json2, yaml, toml, cbor, msgpack and bson formats
+decode the input a second time into a map, which makes strict unmarshaling slower. In
+direct JSON mode, the input is only read once. The msgpack format reads the value into a
+buffer before decoding it, so options set on the msgpack.Decoder don't apply.
+
+Errors returned for missing or invalid fields are created using errors.New. With
+-typed-errors, the generated code returns a *codec.FieldError instead, which can be
+inspected with errors.As. Package codec is github.com/fjl/gencodec/codec.
+
 Other struct tags are carried over as is. The "json", "yaml", "toml" tags can be used to
 rename a field when marshaling.
 
@@ -27,6 +203,18 @@ Example:
 		Renamed  string `json:"otherName"`
 	}
 
+# Embedded Structs
+
+Exported fields of embedded structs are promoted into the generated marshaling code,
+following the rules of package encoding/json. The rules are applied separately for each
+format, using the struct tag of the format: an embedded struct with a name in its tag is
+treated as a regular field. When multiple fields have the same key, the least nested one
+is used. If there are several fields at the same depth, the one with a name in its tag
+is chosen. Otherwise, all of them are ignored. Promoted fields can be targeted by field
+overrides in the same way as regular fields.
+
+Embedded pointers are allocated as needed on unmarshal.
+
 # Field Type Overrides
 
 An invocation of gencodec can specify an additional 'field override' struct from which
@@ -60,6 +248,21 @@ field is ignored.
 		Func string `json:"id"`    // adds the result of foo.Func() to the serialised object under the key id
 	}
 
+# Generic Types
+
+gencodec can generate methods for generic struct types. The field override type of a
+generic type may itself be generic. It must have the same number of type parameters,
+which are matched by position. The type parameters of the original type must satisfy
+the constraints of the override type.
+
+	type Envelope[T any] struct {
+		Items []T
+	}
+
+	type envelopeMarshaling[T any] struct {
+		Items itemList[T]
+	}
+
 # Relaxed Field Conversions
 
 Field types in the override struct must be trivially convertible to the original field
@@ -166,304 +369,262 @@ the input is verified to be "stdout" or "stderr", and the appropriate writer is
 		}
 		return nil
 	}
+
+# Direct JSON Mode
+
+By default, the generated JSON methods convert between the struct and an intermediate
+struct, which is then encoded by package json. With -mode=direct, MarshalJSON appends
+object keys and values to a byte buffer instead. String, boolean and numeric fields are
+encoded directly, other values are encoded individually by json.Marshal. UnmarshalJSON
+scans the input once and decodes each member into its field. Strings, booleans and
+numbers are decoded directly, other values by json.Unmarshal. Like json.Unmarshal, it
+rejects trailing data after the object. Key matching follows package json: exact matches
+are preferred, then keys are compared case-insensitively. Unknown keys are skipped.
+
+The generated code uses package github.com/fjl/gencodec/codec for encoding and decoding
+values. The ",string" json tag option is not supported in direct mode.
 */
 package main
 
 import (
 	"bytes"
+	"errors"
 	"flag"
 	"fmt"
-	"go/importer"
-	"go/token"
-	"go/types"
-	"io"
+	"io/fs"
 	"os"
-	"reflect"
 	"strings"
 
-	"github.com/garslo/gogen"
-	"golang.org/x/tools/go/packages"
-	"golang.org/x/tools/imports"
+	"github.com/fjl/gencodec/gen"
+	"github.com/kylelemons/godebug/diff"
 )
 
 func main() {
 	var (
-		pkgdir    = flag.String("dir", ".", "input package")
+		pkgdir    = flag.String("dir", ".", `input package, use "dir/..." to include subpackages`)
 		output    = flag.String("out", "-", "output file (default is stdout)")
-		typename  = flag.String("type", "", "type to generate methods for")
+		typelist  typeListFlag
 		overrides = flag.String("field-override", "", "type to take field type replacements from")
-		formats   = flag.String("formats", "json", `marshaling formats (e.g. "json,yaml")`)
+		formats   = flag.String("formats", "json", "marshaling formats, comma-separated (supported: "+strings.Join(gen.Formats(), ", ")+")")
+		check     = flag.Bool("check", false, "verify that output files are up to date instead of writing them")
+		allErrors = flag.Bool("all-errors", false, "report all missing required fields on unmarshal")
+		typedErr  = flag.Bool("typed-errors", false, "return *codec.FieldError for invalid fields")
+		validate  = flag.Bool("validate", false, "generate a Validate method")
+		strict    = flag.Bool("strict", false, "reject unknown keys on unmarshal (most formats decode the input twice)")
+		mode      = flag.String("mode", "intermediate", `JSON generation mode ("intermediate" or "direct")`)
+		protoOut  = flag.String("proto-out", "", "write a .proto file for types using the protowire format")
 	)
+	flag.Var(&typelist, "type", `types to generate methods for (e.g. "A,B:bOverride"), default is all annotated types`)
 	flag.Parse()
 
+	if *overrides != "" {
+		if len(typelist) != 1 || typelist[0].FieldOverride != "" {
+			fatal("-field-override can only be used with a single -type")
+		}
+		typelist[0].FieldOverride = *overrides
+	}
 	formatList := strings.Split(*formats, ",")
 	for i := range formatList {
 		formatList[i] = strings.TrimSpace(formatList[i])
 	}
-	cfg := Config{Dir: *pkgdir, Type: *typename, FieldOverride: *overrides, Formats: formatList}
-	code, err := cfg.process()
-	if err != nil {
-		fatal(err)
-	}
-	if *output == "-" {
-		os.Stdout.Write(code)
-	} else if err := os.WriteFile(*output, code, 0644); err != nil {
-		fatal(err)
-	}
-}
-
-func fatal(args ...interface{}) {
-	fmt.Fprintln(os.Stderr, args...)
-	os.Exit(1)
-}
-
-var AllFormats = []string{"json", "yaml", "toml"}
-
-type Config struct {
-	Dir           string   // input package directory
-	Type          string   // type to generate methods for
-	FieldOverride string   // name of struct type for field overrides
-	Formats       []string // defaults to just "json", supported: "json", "yaml"
-	Importer      types.Importer
-	FileSet       *token.FileSet
-}
-
-func (cfg *Config) process() (code []byte, err error) {
-	if cfg.FileSet == nil {
-		cfg.FileSet = token.NewFileSet()
-	}
-	if cfg.Importer == nil {
-		cfg.Importer = importer.Default()
-	}
-	if cfg.Formats == nil {
-		cfg.Formats = []string{"json"}
-	}
-	pkg, err := loadPackage(cfg)
-	if err != nil {
-		return nil, err
-	}
-	typ, err := lookupStructType(pkg.Scope(), cfg.Type)
-	if err != nil {
-		return nil, fmt.Errorf("can't find %s in %q: %v", cfg.Type, pkg.Path(), err)
-	}
-
-	// Construct the marshaling type.
-	mtyp := newMarshalerType(cfg.FileSet, cfg.Importer, typ)
-	if cfg.FieldOverride != "" {
-		otyp, err := lookupStructType(pkg.Scope(), cfg.FieldOverride)
+	cfg := gen.Config{Dir: *pkgdir, Types: typelist, Formats: formatList, AllErrors: *allErrors, TypedErrors: *typedErr, Validate: *validate, Strict: *strict, Mode: *mode}
+	var files []gen.File
+	if len(typelist) == 0 {
+		// Without -type, all types annotated with a directive are processed.
+		if *output != "-" {
+			fatal("-out can't be used without -type")
+		}
+		if *protoOut != "" {
+			fatal("-proto-out can't be used without -type")
+		}
+		var (
+			diags []gen.Diagnostic
+			err   error
+		)
+		files, diags, err = gen.GenerateDirectives(cfg)
+		warn(diags)
 		if err != nil {
-			return nil, fmt.Errorf("can't find field replacement type %s: %v", cfg.FieldOverride, err)
+			fatal(err)
 		}
-
-		err = mtyp.loadOverrides(otyp)
+	} else {
+		var (
+			code, schema []byte
+			diags        []gen.Diagnostic
+			err          error
+		)
+		if *protoOut != "" {
+			code, schema, diags, err = gen.GenerateWithProto(cfg)
+		} else {
+			code, diags, err = gen.Generate(cfg)
+		}
+		warn(diags)
 		if err != nil {
-			return nil, err
+			fatal(err)
+		}
+		if *protoOut != "" {
+			files = append(files, gen.File{Path: *protoOut, Code: schema})
+		}
+		if *output == "-" && !*check {
+			os.Stdout.Write(code)
+		} else if *output == "-" {
+			fatal("-check requires -out")
+		} else {
+			files = append(files, gen.File{Path: *output, Code: code})
 		}
 	}
 
-	// Generate and format the output. Formatting uses goimports because it
-	// removes unused imports.
-	code, err = generate(mtyp, cfg)
-	if err != nil {
-		return nil, err
+	if *check {
+		stale := false
+		for _, f := range files {
+			if !checkOutput(f) {
+				stale = true
+			}
+		}
+		if stale {
+			os.Exit(1)
+		}
+		return
 	}
-	opt := &imports.Options{Comments: true, TabIndent: true, TabWidth: 8}
-	code, err = imports.Process("", code, opt)
-	if err != nil {
-		panic(fmt.Errorf("BUG: can't gofmt generated code: %v", err))
+	for _, f := range files {
+		if err := os.WriteFile(f.Path, f.Code, 0644); err != nil {
+			fatal(err)
+		}
 	}
-	return code, nil
 }
 
-func loadPackage(cfg *Config) (*types.Package, error) {
-	pcfg := &packages.Config{
-		Mode:  packages.NeedTypes | packages.NeedDeps | packages.NeedImports,
-		Tests: true,
-		Dir:   cfg.Dir,
-	}
-	ps, err := packages.Load(pcfg, ".")
-	if err != nil {
-		return nil, err
+// checkOutput compares generated code against the existing file content.
+// If they differ, it prints a diff and returns false.
+func checkOutput(f gen.File) bool {
+	current, err := os.ReadFile(f.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fatal(err)
 	}
-	if len(ps) == 0 {
-		return nil, fmt.Errorf("can't find go package in %s", cfg.Dir)
+	if bytes.Equal(current, f.Code) {
+		return true
 	}
-	return ps[0].Types, nil
-}
-
-func generate(mtyp *marshalerType, cfg *Config) ([]byte, error) {
-	w := new(bytes.Buffer)
-	fmt.Fprint(w, "// Code generated by github.com/fjl/gencodec. DO NOT EDIT.\n\n")
-	fmt.Fprintln(w, "package", mtyp.orig.Obj().Pkg().Name())
-	fmt.Fprintln(w)
-	mtyp.scope.writeImportDecl(w)
-	fmt.Fprintln(w)
-	if mtyp.override != nil {
-		writeUseOfOverride(w, mtyp.override, mtyp.scope.qualify)
-	}
-	for _, format := range cfg.Formats {
-		var genMarshal, genUnmarshal gogen.Function
-		switch format {
-		case "json":
-			genMarshal = genMarshalJSON(mtyp)
-			genUnmarshal = genUnmarshalJSON(mtyp)
-		case "yaml":
-			genMarshal = genMarshalYAML(mtyp)
-			genUnmarshal = genUnmarshalYAML(mtyp)
-		case "toml":
-			genMarshal = genMarshalTOML(mtyp)
-			genUnmarshal = genUnmarshalTOML(mtyp)
-		default:
-			return nil, fmt.Errorf("unknown format: %q", format)
-		}
-		fmt.Fprintf(w, "// %s marshals as %s.", genMarshal.Name, strings.ToUpper(format))
-		fmt.Fprintln(w)
-		writeFunction(w, mtyp.fs, genMarshal)
-		fmt.Fprintln(w)
-		fmt.Fprintf(w, "// %s unmarshals from %s.", genUnmarshal.Name, strings.ToUpper(format))
-		fmt.Fprintln(w)
-		writeFunction(w, mtyp.fs, genUnmarshal)
-		fmt.Fprintln(w)
-	}
-	return w.Bytes(), nil
+	fmt.Fprintf(os.Stderr, "%s is not up to date:\n%s", f.Path, unifiedDiff(f.Path, string(current), string(f.Code)))
+	return false
 }
 
-func writeUseOfOverride(w io.Writer, n *types.Named, qf types.Qualifier) {
-	name := types.TypeString(types.NewPointer(n), qf)
-	fmt.Fprintf(w, "var _ = (%s)(nil)\n", name)
+type diffLine struct {
+	op   byte   // ' ', '-' or '+'
+	text string // line including its newline, if any
 }
 
-// marshalerType represents the intermediate struct type used during marshaling.
-// This is the input data to all the Go code templates.
-type marshalerType struct {
-	name     string
-	Fields   []*marshalerField
-	fs       *token.FileSet
-	orig     *types.Named
-	override *types.Named
-	scope    *fileScope
+// splitLines splits s into lines. Each line keeps its newline, so a last line
+// without newline differs from the same line with newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
 }
 
-// marshalerField represents a field of the intermediate marshaling type.
-type marshalerField struct {
-	name     string
-	typ      types.Type
-	origTyp  types.Type
-	tag      string
-	function *types.Func // map to a function instead of a field
+// hunkRange formats the line range of a hunk. Empty ranges start at the line
+// before the hunk.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
 }
 
-func newMarshalerType(fs *token.FileSet, imp types.Importer, typ *types.Named) *marshalerType {
-	mtyp := &marshalerType{name: typ.Obj().Name(), fs: fs, orig: typ}
-	styp := typ.Underlying().(*types.Struct)
-	mtyp.scope = newFileScope(imp, typ.Obj().Pkg())
-	mtyp.scope.addReferences(styp)
-
-	// Add packages which are always needed.
-	mtyp.scope.addImport("encoding/json")
-	mtyp.scope.addImport("errors")
-
-	for i := 0; i < styp.NumFields(); i++ {
-		f := styp.Field(i)
-		if !f.Exported() {
-			continue
+// unifiedDiff creates a unified diff with three lines of context.
+func unifiedDiff(name, a, b string) string {
+	var lines []diffLine
+	for _, c := range diff.DiffChunks(splitLines(a), splitLines(b)) {
+		for _, l := range c.Deleted {
+			lines = append(lines, diffLine{'-', l})
 		}
-		if f.Anonymous() {
-			fmt.Fprintf(os.Stderr, "Warning: ignoring embedded field %s\n", f.Name())
-			continue
+		for _, l := range c.Added {
+			lines = append(lines, diffLine{'+', l})
 		}
-
-		mf := &marshalerField{
-			name:    f.Name(),
-			typ:     f.Type(),
-			origTyp: f.Type(),
-			tag:     styp.Tag(i),
+		for _, l := range c.Equal {
+			lines = append(lines, diffLine{' ', l})
 		}
-
-		mtyp.Fields = append(mtyp.Fields, mf)
 	}
-
-	return mtyp
-}
-
-// findFunction returns a function with `name` that accepts no arguments
-// and returns a single value that is convertible to the given to type.
-func findFunction(typ *types.Named, name string, to types.Type) (*types.Func, types.Type) {
-	for i := 0; i < typ.NumMethods(); i++ {
-		fun := typ.Method(i)
-		if fun.Name() != name || !fun.Exported() {
-			continue
+	// Compute line numbers in a and b for each line.
+	apos := make([]int, len(lines)+1)
+	bpos := make([]int, len(lines)+1)
+	for i, l := range lines {
+		apos[i+1], bpos[i+1] = apos[i], bpos[i]
+		if l.op != '+' {
+			apos[i+1]++
 		}
-		sign := fun.Type().(*types.Signature)
-		if sign.Params().Len() != 0 || sign.Results().Len() != 1 {
-			continue
-		}
-		if err := checkConvertible(sign.Results().At(0).Type(), to); err == nil {
-			return fun, sign.Results().At(0).Type()
+		if l.op != '-' {
+			bpos[i+1]++
 		}
 	}
-	return nil, nil
-}
 
-// loadOverrides sets field types of the intermediate marshaling type from
-// matching fields of otyp.
-func (mtyp *marshalerType) loadOverrides(otyp *types.Named) error {
-	s := otyp.Underlying().(*types.Struct)
-	for i := 0; i < s.NumFields(); i++ {
-		of := s.Field(i)
-		if of.Anonymous() || !of.Exported() {
-			return fmt.Errorf("%v: field override type cannot have embedded or unexported fields", mtyp.fs.Position(of.Pos()))
+	const context = 3
+	out := new(strings.Builder)
+	fmt.Fprintf(out, "--- %s\n+++ %s (generated)\n", name, name)
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
 		}
-		f := mtyp.fieldByName(of.Name())
-		if f == nil {
-			// field not defined in original type, check if it maps to a suitable function and add it as an override
-			if fun, retType := findFunction(mtyp.orig, of.Name(), of.Type()); fun != nil {
-				f = &marshalerField{name: fun.Name(), origTyp: retType, typ: of.Type(), function: fun, tag: s.Tag(i)}
-				mtyp.Fields = append(mtyp.Fields, f)
-			} else {
-				return fmt.Errorf("%v: no matching field or function for %s in original type %s", mtyp.fs.Position(of.Pos()), of.Name(), mtyp.name)
+		// Find the end of the hunk. Changes separated by fewer than
+		// 2*context equal lines are merged into one hunk.
+		end := i + 1
+		for j := end; j < len(lines); j++ {
+			if lines[j].op != ' ' {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
 			}
 		}
-		if err := checkConvertible(of.Type(), f.origTyp); err != nil {
-			return fmt.Errorf("%v: invalid field override: %v", mtyp.fs.Position(of.Pos()), err)
+		start, stop := max(i-context, 0), min(end+context, len(lines))
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(apos[start], apos[stop]-apos[start]), hunkRange(bpos[start], bpos[stop]-bpos[start]))
+		for _, l := range lines[start:stop] {
+			out.WriteByte(l.op)
+			out.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
 		}
-		f.typ = of.Type()
+		i = end
 	}
-	mtyp.scope.addReferences(s)
-	mtyp.override = otyp
-	return nil
+	return out.String()
 }
 
-func (mtyp *marshalerType) fieldByName(name string) *marshalerField {
-	for _, f := range mtyp.Fields {
-		if f.name == name {
-			return f
-		}
+// warn prints problems found in the input package.
+func warn(diags []gen.Diagnostic) {
+	for _, d := range diags {
+		fmt.Fprintln(os.Stderr, "warning:", d)
 	}
-	return nil
 }
 
-// isRequired returns whether the field is required when decoding the given format.
-func (mf *marshalerField) isRequired(format string) bool {
-	rtag := reflect.StructTag(mf.tag)
-	req := rtag.Get("gencodec") == "required"
-	// Fields with json:"-" must be treated as optional. This also works
-	// for the other supported formats.
-	return req && !strings.HasPrefix(rtag.Get(format), "-")
+func fatal(args ...interface{}) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
 }
 
-// encodedName returns the alternative field name assigned by the format's struct tag.
-func (mf *marshalerField) encodedName(format string) string {
-	val := reflect.StructTag(mf.tag).Get(format)
-	if comma := strings.Index(val, ","); comma != -1 {
-		val = val[:comma]
-	}
-	if val == "" || val == "-" {
-		return uncapitalize(mf.name)
+// typeListFlag is the value of the -type flag. It accepts a comma-separated
+// list of type names, each optionally followed by ":" and the name of its
+// field override type. The flag can be given more than once.
+type typeListFlag []gen.TypeConfig
+
+func (f *typeListFlag) String() string {
+	var names []string
+	for _, tc := range *f {
+		if tc.FieldOverride != "" {
+			names = append(names, tc.Name+":"+tc.FieldOverride)
+		} else {
+			names = append(names, tc.Name)
+		}
 	}
-	return val
+	return strings.Join(names, ",")
 }
 
-func uncapitalize(s string) string {
-	return strings.ToLower(s[:1]) + s[1:]
+func (f *typeListFlag) Set(value string) error {
+	for _, elem := range strings.Split(value, ",") {
+		name, override, _ := strings.Cut(strings.TrimSpace(elem), ":")
+		if name == "" {
+			return fmt.Errorf("invalid type list %q", value)
+		}
+		*f = append(*f, gen.TypeConfig{Name: name, FieldOverride: override})
+	}
+	return nil
 }
@@ -5,52 +5,55 @@
 package main
 
 import (
-	"os"
-	"path/filepath"
+	"strings"
 	"testing"
-
-	"github.com/kylelemons/godebug/diff"
 )
 
-// 'golden' tests. These tests compare the output code with the expected
-// code in internal/tests/*/output.go. The expected can be updated using
-//
-//    go generate ./internal/...
-
-func TestGolden(t *testing.T) {
-	tests := []Config{
-		Config{Dir: "mapconv", Type: "X", FieldOverride: "Xo", Formats: AllFormats},
-		Config{Dir: "sliceconv", Type: "X", FieldOverride: "Xo", Formats: AllFormats},
-		Config{Dir: "arrayconv", Type: "X", FieldOverride: "Xo", Formats: []string{"json"}},
-		Config{Dir: "nameclash", Type: "Y", FieldOverride: "yo", Formats: AllFormats},
-		Config{Dir: "omitempty", Type: "X", FieldOverride: "Xo", Formats: AllFormats},
-		Config{Dir: "reqfield", Type: "X", Formats: []string{"json"}},
-		Config{Dir: "ftypes", Type: "X", Formats: []string{"json"}},
-		Config{Dir: "funcoverride", Type: "Z", FieldOverride: "Zo", Formats: AllFormats},
-		Config{Dir: "ifaceoverride", Type: "Cfg", FieldOverride: "cfgOverride", Formats: AllFormats},
-		Config{Dir: "alias", Type: "X", Formats: []string{"json"}},
-	}
-	for _, test := range tests {
-		test := test
-		t.Run(test.Dir, func(t *testing.T) {
-			t.Parallel()
-			runGoldenTest(t, test)
-		})
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\ntwo\n3\n4\n5\n6\n7\n8\n9\n10\n11\n"
+	want := `--- f
++++ f (generated)
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -9,4 +9,3 @@
+ 9
+ 10
+ 11
+-12
+`
+	if d := unifiedDiff("f", a, b); d != want {
+		t.Errorf("wrong diff:\n%s", d)
 	}
-}
-
-func runGoldenTest(t *testing.T, cfg Config) {
-	cfg.Dir = filepath.Join("internal", "tests", cfg.Dir)
-	want, err := os.ReadFile(filepath.Join(cfg.Dir, "output.go"))
-	if err != nil {
-		t.Fatal(err)
+	// A missing newline at the end of the file is a change of the last line.
+	want = `--- f
++++ f (generated)
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++12
+\ No newline at end of file
+`
+	if d := unifiedDiff("f", a, strings.TrimSuffix(a, "\n")); d != want {
+		t.Errorf("wrong diff for missing newline:\n%s", d)
 	}
-
-	code, err := cfg.process()
-	if err != nil {
-		t.Fatal(err)
+	// Empty ranges start at the line before the hunk.
+	want = `--- f
++++ f (generated)
+@@ -0,0 +1,1 @@
++1
+`
+	if d := unifiedDiff("f", "", "1\n"); d != want {
+		t.Errorf("wrong diff for empty input:\n%s", d)
 	}
-	if d := diff.Diff(string(want), string(code)); d != "" {
-		t.Errorf("output mismatch\n\n%s", d)
+	if d := unifiedDiff("f", a, a); d != "--- f\n+++ f (generated)\n" {
+		t.Errorf("non-empty diff for equal input:\n%s", d)
 	}
 }